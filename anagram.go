@@ -0,0 +1,62 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Anagrams returns every word in the trie that can be spelled from letters,
+// using each letter at most as many times as it appears, sorted
+// alphabetically. Letters need not all be used.
+//
+// It walks the trie once, taking a letter from the pool on the way down each
+// branch and returning it on the way back, so branches spelling letters the
+// pool has run out of are never visited.
+func (t *TrieNode) Anagrams(letters string) []string {
+	return anagrams(t, letters)
+}
+
+// anagrams is Anagrams over any wordTrie.
+func anagrams(trie wordTrie, letters string) []string {
+	pool := make(map[rune]int)
+	for _, char := range letters {
+		pool[char]++
+	}
+
+	var words []string
+	var walk func(node trieCursor, word []rune)
+	walk = func(node trieCursor, word []rune) {
+		if node.isWord() && len(word) > 0 {
+			words = append(words, string(word))
+		}
+		node.children(func(char rune, child trieCursor) {
+			if pool[char] == 0 {
+				return
+			}
+			pool[char]--
+			walk(child, append(word[:len(word):len(word)], char))
+			pool[char]++
+		})
+	}
+	walk(trie.at(""), nil)
+
+	sort.Strings(words)
+	return words
+}
+
+// anagramResults finds the words spellable from the combined letters of
+// tiles, ignoring tile boundaries, longest first and then alphabetically.
+// The results carry no tiles or score since no tile sequence is implied.
+func anagramResults(trie wordTrie, tiles []string) []Result {
+	words := anagrams(trie, strings.Join(tiles, ""))
+	sort.SliceStable(words, func(i, j int) bool {
+		return utf8.RuneCountInString(words[i]) > utf8.RuneCountInString(words[j])
+	})
+
+	results := make([]Result, len(words))
+	for i, word := range words {
+		results[i] = Result{Word: word}
+	}
+	return results
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnagrams_RespectsLetterCounts(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"tea", "eat", "teat", "tee", "at", "ate", "tease"} {
+		trie.Insert(word)
+	}
+
+	got := trie.Anagrams("tae")
+	want := []string{"at", "ate", "eat", "tea"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Anagrams(tae) = %v, expected %v", got, want)
+	}
+
+	// "teat" needs two t's and "tee" two e's; one extra t allows only teat
+	got = trie.Anagrams("teat")
+	want = []string{"at", "ate", "eat", "tea", "teat"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Anagrams(teat) = %v, expected %v", got, want)
+	}
+
+	if got := trie.Anagrams(""); len(got) != 0 {
+		t.Errorf("Expected no anagrams from an empty pool, got %v", got)
+	}
+}
+
+func TestRun_Anagram(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'tea',n,1,3).\ns(100000002,1,'teat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "et\na\n")
+
+	var buf bytes.Buffer
+	found, err := runCountingMatches(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		anagram:        true,
+		format:         outputQuiet,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("runCountingMatches() error = %v", err)
+	}
+
+	// Concatenating tiles gives only "eta"/"aet"; the anagram pool finds
+	// "tea", but never "teas" or "teat", which need letters the pool lacks
+	if got := strings.Fields(buf.String()); !reflect.DeepEqual(got, []string{"tea"}) || found != 1 {
+		t.Errorf("Expected only tea (found=1), got %v (found=%d)", got, found)
+	}
+}
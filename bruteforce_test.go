@@ -0,0 +1,76 @@
+package main
+
+import "strings"
+
+// generatePermutations generates all possible word combinations from puzzle tiles.
+// It creates combinations of 1 to maxLines tiles, then generates all permutations
+// of each combination.
+func generatePermutations(lines []string, maxLines int) []string {
+	var results []string
+
+	for i := 1; i <= maxLines; i++ {
+		combinations := combinations(lines, i)
+		for _, combo := range combinations {
+			perms := permutations(combo)
+			for _, perm := range perms {
+				results = append(results, strings.Join(perm, ""))
+			}
+		}
+	}
+	return results
+}
+
+// permutations generates all permutations of a slice of strings.
+func permutations(arr []string) [][]string {
+	var result [][]string
+
+	if len(arr) == 0 {
+		return result
+	}
+
+	if len(arr) == 1 {
+		return [][]string{arr}
+	}
+
+	for i := 0; i < len(arr); i++ {
+		current := arr[i]
+		remaining := append(append([]string{}, arr[:i]...), arr[i+1:]...)
+		subPerms := permutations(remaining)
+		for _, subPerm := range subPerms {
+			result = append(result, append([]string{current}, subPerm...))
+		}
+	}
+
+	return result
+}
+
+// combinations generates all combinations of r elements from arr.
+func combinations(arr []string, r int) [][]string {
+	var result [][]string
+	var f func([]string, int, []string)
+	f = func(arr []string, n int, temp []string) {
+		if len(temp) == r {
+			result = append(result, append([]string{}, temp...))
+			return
+		}
+		for i := n; i < len(arr); i++ {
+			f(arr, i+1, append(temp, arr[i]))
+		}
+	}
+	f(arr, 0, []string{})
+	return result
+}
+
+// checkInTrie returns the permutations that are dictionary words, in order.
+// Printing them is left to a Printer.
+func checkInTrie(trie *TrieNode, permutations []string, debug bool) []string {
+	var found []string
+	for _, perm := range permutations {
+		if trie.Search(perm) {
+			found = append(found, perm)
+		} else if debug {
+			logger.Debug("not found in trie", "word", perm)
+		}
+	}
+	return found
+}
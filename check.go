@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordCheck is what --check reports about one word.
+type wordCheck struct {
+	Word         string
+	InDictionary bool
+	// Problem says why no run of tiles could spell Word in a Quartile
+	// puzzle, or is empty when one could.
+	Problem string
+}
+
+// checkWord looks word up in the trie, lowercased as the loaders store
+// words, and checks whether it could appear in a puzzle at all: tiles hold
+// only letters, at least defaultMinTileLength of them.
+func checkWord(trie wordTrie, word string) wordCheck {
+	word = normalizeWord(strings.TrimSpace(word))
+	check := wordCheck{Word: word, InDictionary: trie.Search(word)}
+
+	if invalid := strings.IndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }); invalid >= 0 {
+		r, _ := utf8.DecodeRuneInString(word[invalid:])
+		check.Problem = fmt.Sprintf("contains %q, which no tile holds", r)
+	} else if length := utf8.RuneCountInString(word); length < defaultMinTileLength {
+		check.Problem = fmt.Sprintf("has %d letter(s), fewer than any tile", length)
+	}
+	return check
+}
+
+// printWordCheck reports whether the word is in the dictionary and whether
+// it is quartile-legal.
+func printWordCheck(w io.Writer, check wordCheck) {
+	if check.InDictionary {
+		fmt.Fprintf(w, "%q is in the dictionary\n", check.Word)
+	} else {
+		fmt.Fprintf(w, "%q is not in the dictionary\n", check.Word)
+	}
+	if check.Problem == "" {
+		fmt.Fprintf(w, "%q is quartile-legal\n", check.Word)
+	} else {
+		fmt.Fprintf(w, "%q is not quartile-legal: it %s\n", check.Word, check.Problem)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRun_CheckWord(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'castle',n,1,3).")
+
+	tests := []struct {
+		word      string
+		wantFound int
+		want      []string
+	}{
+		{"Castles", 1, []string{`"castles" is in the dictionary`, `"castles" is quartile-legal`}},
+		{"moat", 0, []string{`"moat" is not in the dictionary`, `"moat" is quartile-legal`}},
+		{"it's", 0, []string{`"it's" is not quartile-legal: it contains '\''`}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		opts := options{dictionaryPath: dictPath, checkWord: tt.word, diagnostics: &bytes.Buffer{}}
+		found, err := runCountingMatches(context.Background(), opts, &buf)
+		if err != nil {
+			t.Fatalf("--check %s: unexpected error %v", tt.word, err)
+		}
+		if found != tt.wantFound {
+			t.Errorf("--check %s: found = %d, expected %d", tt.word, found, tt.wantFound)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("--check %s: expected %q in output, got:\n%s", tt.word, want, buf.String())
+			}
+		}
+	}
+}
+
+func TestCheckWord_TooShort(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("a")
+
+	check := checkWord(trie, "a")
+	if !check.InDictionary || !strings.Contains(check.Problem, "fewer than any tile") {
+		t.Errorf("checkWord(a) = %+v, expected a word too short for any tile", check)
+	}
+}
@@ -0,0 +1,374 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	// Ctrl-C stops loading or solving early instead of killing the process,
+	// so a long solve still prints what it has found. Only the first one is
+	// caught; a second Ctrl-C kills the process as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	code := dispatch(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// command is the entry point of a subcommand. It parses its own flags from
+// args and returns the process exit code.
+type command func(ctx context.Context, args []string, stdout, stderr io.Writer) int
+
+// commands maps each subcommand name to its entry point.
+var commands = map[string]command{
+	"solve":       runSolveCommand,
+	"build-cache": runBuildCacheCommand,
+	"export":      runExportCommand,
+}
+
+// dispatch runs the subcommand named by args[0]. When args[0] is not a
+// subcommand, usually because it is a flag, all of args go to solve, so
+// command lines from before subcommands existed keep working.
+func dispatch(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			return cmd(ctx, args[1:], stdout, stderr)
+		}
+	}
+	return runSolveCommand(ctx, args, stdout, stderr)
+}
+
+// dictionaryFlags holds the flags that choose, load, and filter the
+// dictionary, which every subcommand accepts.
+type dictionaryFlags struct {
+	path              *string
+	format            *string
+	includeSatellites *bool
+	includeProper     *bool
+	splitPhrases      *bool
+	agentNouns        *bool
+	noGenerateForms   *bool
+	strictProlog      *bool
+	allowlistPath     *string
+	blocklistPath     *string
+	variantsPath      *string
+	safe              *bool
+	safeListPath      *string
+}
+
+// addDictionaryFlags registers the dictionary flags on fs.
+func addDictionaryFlags(fs *flag.FlagSet) *dictionaryFlags {
+	return &dictionaryFlags{
+		path:              fs.String("dictionary", "", "Path to the dictionary file (default: a small built-in word list)"),
+		format:            fs.String("dictionary-format", formatAuto, "Dictionary format: auto, wordnet, plain, scowl, or trie"),
+		includeSatellites: fs.Bool("include-satellites", true, "Load WordNet adjective satellites; set false to skip these mostly duplicate entries"),
+		includeProper:     fs.Bool("include-proper", false, "Keep capitalized dictionary entries such as place names, lowercased"),
+		splitPhrases:      fs.Bool("split-phrases", false, "Insert each word of multi-word WordNet entries instead of the whole phrase"),
+		agentNouns:        fs.Bool("agent-nouns", false, "Also generate -er agent nouns for WordNet verbs (run→runner)"),
+		noGenerateForms:   fs.Bool("no-generate-forms", false, "Insert only the word forms WordNet lists, without generated plurals, verb forms, or comparatives"),
+		strictProlog:      fs.Bool("strict-prolog", false, "Parse WordNet lines with a Prolog tokenizer instead of the default pattern match"),
+		allowlistPath:     fs.String("allowlist", "", "Path to a file of extra words to add to the dictionary"),
+		blocklistPath:     fs.String("blocklist", "", "Path to a file of words to remove from the dictionary"),
+		variantsPath:      fs.String("variants", "", "Path to a file of spelling pairs such as \"color colour\"; adds each variant whose base word is loaded"),
+		safe:              fs.Bool("safe", false, "Remove offensive words from the dictionary using the built-in list"),
+		safeListPath:      fs.String("safe-list", "", "Path to an offensive word list to use instead of the built-in one (implies --safe)"),
+	}
+}
+
+// apply copies the parsed dictionary flags into opts.
+func (f *dictionaryFlags) apply(opts *options) {
+	opts.dictionaryPath = *f.path
+	opts.dictionaryFormat = *f.format
+	opts.skipSatellites = !*f.includeSatellites
+	opts.includeProper = *f.includeProper
+	opts.splitPhrases = *f.splitPhrases
+	opts.agentNouns = *f.agentNouns
+	opts.noGeneratedForms = *f.noGenerateForms
+	opts.strictProlog = *f.strictProlog
+	opts.allowlistPath = *f.allowlistPath
+	opts.blocklistPath = *f.blocklistPath
+	opts.variantsPath = *f.variantsPath
+	opts.safe = *f.safe
+	opts.safeListPath = *f.safeListPath
+}
+
+// commonFlags holds the flags every subcommand accepts besides the
+// dictionary ones.
+type commonFlags struct {
+	debug      *bool
+	logLevel   *string
+	configPath *string
+	help       *bool
+}
+
+// addCommonFlags registers the common flags on fs.
+func addCommonFlags(fs *flag.FlagSet) *commonFlags {
+	return &commonFlags{
+		debug:      fs.Bool("debug", false, "Enable debug mode (implies --log-level debug)"),
+		logLevel:   fs.String("log-level", logLevelWarn, "Diagnostics written to stderr: debug, info, or warn"),
+		configPath: fs.String("config", "", "Path to a JSON file of default flag values"),
+		help:       fs.Bool("help", false, "Show usage information"),
+	}
+}
+
+// parseFlags parses args into fs, applies any --config file, and sets up
+// the logger. It returns false with the exit code when the command should
+// stop, either because of an error or because help was printed.
+func parseFlags(fs *flag.FlagSet, common *commonFlags, args []string, stderr io.Writer) (slog.Level, bool, int) {
+	fs.SetOutput(stderr)
+	fs.Usage = func() {}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printHelp()
+			return 0, false, exitFound
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Run with --help for usage information\n")
+		return 0, false, exitError
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", fs.Arg(0))
+		fmt.Fprintf(stderr, "Run with --help for usage information\n")
+		return 0, false, exitError
+	}
+
+	if *common.configPath != "" {
+		if err := applyConfig(fs, *common.configPath); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 0, false, exitError
+		}
+	}
+
+	level, err := parseLogLevel(*common.logLevel)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 0, false, exitError
+	}
+	if *common.debug {
+		level = slog.LevelDebug
+	}
+	logger = newLogger(stderr, level)
+
+	if *common.help {
+		printHelp()
+		return level, false, exitFound
+	}
+	return level, true, 0
+}
+
+// runBuildCacheCommand loads the dictionary and saves the built trie to
+// --output as a trie file, which later runs load with --dictionary without
+// parsing WordNet or generating forms again.
+func runBuildCacheCommand(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("build-cache", flag.ContinueOnError)
+	common := addCommonFlags(fs)
+	dictionary := addDictionaryFlags(fs)
+	output := fs.String("output", "", "Path to write the trie file")
+	level, ok, code := parseFlags(fs, common, args, stderr)
+	if !ok {
+		return code
+	}
+	if *output == "" {
+		fmt.Fprintf(stderr, "Error: build-cache requires --output\n")
+		return exitError
+	}
+
+	opts := options{cachePath: *output, debug: level == slog.LevelDebug, diagnostics: stderr}
+	dictionary.apply(&opts)
+	return runCommand(ctx, opts, stdout, stderr)
+}
+
+// runExportCommand loads the dictionary and writes every word to --output
+// as a sorted plain wordlist, like --export-dict.
+func runExportCommand(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	common := addCommonFlags(fs)
+	dictionary := addDictionaryFlags(fs)
+	output := fs.String("output", "", "Path to write the wordlist")
+	level, ok, code := parseFlags(fs, common, args, stderr)
+	if !ok {
+		return code
+	}
+	if *output == "" {
+		fmt.Fprintf(stderr, "Error: export requires --output\n")
+		return exitError
+	}
+
+	opts := options{exportDictPath: *output, debug: level == slog.LevelDebug, diagnostics: stderr}
+	dictionary.apply(&opts)
+	return runCommand(ctx, opts, stdout, stderr)
+}
+
+// runCommand runs the solver with opts, reports any error on stderr, and
+// returns the exit code.
+func runCommand(ctx context.Context, opts options, stdout, stderr io.Writer) int {
+	found, err := runCountingMatches(ctx, opts, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+	}
+	return exitCode(opts, found, err)
+}
+
+// runSolveCommand solves puzzles, the default subcommand.
+func runSolveCommand(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("solve", flag.ContinueOnError)
+	common := addCommonFlags(fs)
+	dictionary := addDictionaryFlags(fs)
+	var puzzlePaths stringList
+	fs.Var(&puzzlePaths, "puzzle", "Path to a puzzle file, directory, or glob pattern (repeatable)")
+	historyPath := fs.String("history", "", "Path to a JSON file recording each solve")
+	validateForms := fs.String("validate-forms", "", "Path to a reference wordlist; report generated WordNet forms missing from it and exit")
+	showHistory := fs.Bool("show-history", false, "Print solve history and exit")
+	interactive := fs.Bool("interactive", false, "Solve puzzles typed on stdin, loading the dictionary once")
+	stats := fs.Bool("stats", false, "Print candidate counts and phase timings")
+	limit := fs.Int("limit", 0, "Print only the first N results after sorting (0 for no limit)")
+	tileWeighted := fs.Bool("tile-frequency-weighted", false, "With --limit, explore high-yield tiles first and stop after N words")
+	minTileLength := fs.Int("min-tile-length", defaultMinTileLength, "Warn about tiles with fewer letters than this")
+	maxTileLength := fs.Int("max-tile-length", defaultMaxTileLength, "Warn about tiles with more letters than this")
+	strictTiles := fs.Bool("strict-tiles", false, "Fail instead of warning when a tile's length is out of range")
+	exactTiles := fs.Int("tiles", 0, "Only show words formed from exactly N tiles")
+	contains := fs.String("contains", "", "Only show words containing this substring")
+	maxTiles := fs.Int("max-tiles", quartileMaxTiles, "Most tiles a single word may use")
+	anagram := fs.Bool("anagram", false, "List dictionary words spelled from the combined tile letters, ignoring tile boundaries")
+	hint := fs.Bool("hint", false, "Reveal one tile of one quartile instead of listing the words")
+	decomposeWord := fs.String("decompose", "", "Show which tile sequences build a word instead of listing the words")
+	seed := fs.Int64("seed", 0, "Seed for randomized features such as --hint (0 for no randomness)")
+	solution := fs.Bool("solution", false, "Find quartiles that together use every tile exactly once")
+	maxSolutions := fs.Int("max-solutions", 1, "Most partitions --solution reports (0 for all)")
+	allowTileReuse := fs.Bool("allow-tile-reuse", false, "Let --solution use a tile in more than one word")
+	coverage := fs.Bool("coverage", false, "List tiles that no found word uses")
+	tileStats := fs.Bool("tile-stats", false, "Count how many found words use each tile")
+	maxScore := fs.Bool("max-score", false, "Report the best score from found words that share no tile")
+	stem := fs.Bool("stem", false, "Also match words that inflect a dictionary word, e.g. jumped from jump, noting the base word")
+	partialLastTile := fs.Bool("allow-partial-last-tile", false, "Also list near misses whose last tile is one edit from completing a word, noting the word")
+	prefixCache := fs.Bool("prefix-cache", false, "Memoize trie prefix lookups during each solve; the hit rate is logged at debug level")
+	suggest := fs.Bool("suggest", false, "When no quartile is found, show words one edit from a four-tile arrangement")
+	frequencyPath := fs.String("frequency", "", "Path to a word frequency list used by --order frequency")
+	exportDictPath := fs.String("export-dict", "", "Write the loaded dictionary to this path as a sorted plain wordlist")
+	check := fs.String("check", "", "Report whether a word is in the dictionary and quartile-legal, then exit")
+	timeout := fs.Duration("timeout", 0, "Stop solving after this long and show partial results (e.g. 2s)")
+	maxCandidates := fs.Int("max-candidates", defaultMaxCandidates, "Refuse puzzles projecting more tile arrangements than this (0 for no limit)")
+	dryRun := fs.Bool("dry-run", false, "Validate the dictionary and puzzle and report the projected search size without solving")
+	scores := fs.String("scores", "", "Points per tile count, e.g. 3=5,4=10 (unlisted counts keep 1/2/4/8)")
+	format := fs.String("format", outputText, "Output format: text, json, quiet, or csv")
+	showTiles := fs.Bool("show-tiles", false, "Show each word split into the tiles that build it, e.g. ca|st|le")
+	quiet := fs.Bool("quiet", false, "Print only the found words, one per line (same as --format quiet)")
+	order := fs.String("order", orderTiles, "Result order: tiles, rarity to list rarer-letter words first, or frequency to list common words first, within each tile count")
+	lenient := fs.Bool("lenient", false, "Strip non-letter characters from tiles with a warning instead of failing")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	forceColor := fs.Bool("color", false, "Force colored output, even when NO_COLOR is set or stdout is not a terminal")
+	level, ok, code := parseFlags(fs, common, args, stderr)
+	if !ok {
+		return code
+	}
+
+	colorEnabled = shouldUseColor(*noColor, *forceColor, os.Stdout)
+
+	if *showHistory {
+		if err := showHistoryFile(*historyPath, stdout); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitError
+		}
+		return exitFound
+	}
+
+	if *validateForms != "" {
+		opts := options{debug: level == slog.LevelDebug}
+		dictionary.apply(&opts)
+		if err := runFormAudit(opts.dictionaryPath, *validateForms, opts.loadOptions(), stdout); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitError
+		}
+		return exitFound
+	}
+
+	if *maxTiles < 1 {
+		fmt.Fprintf(stderr, "Error: --max-tiles must be at least 1\n")
+		return exitError
+	}
+
+	if len(puzzlePaths) == 0 && !*interactive && *exportDictPath == "" && *check == "" {
+		fmt.Fprintf(stderr, "Error: --puzzle is required\n")
+		fmt.Fprintf(stderr, "Run with --help for usage information\n")
+		return exitError
+	}
+
+	outputFormat, err := resolveFormat(*format, *quiet)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	var scoreOverrides scoreTable
+	if *scores != "" {
+		table, err := parseScoreTable(*scores)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return exitError
+		}
+		scoreOverrides = table
+	}
+
+	opts := options{
+		puzzlePaths:     puzzlePaths,
+		debug:           level == slog.LevelDebug,
+		historyPath:     *historyPath,
+		interactive:     *interactive,
+		stats:           *stats,
+		exactTiles:      *exactTiles,
+		contains:        *contains,
+		minTileLength:   *minTileLength,
+		maxTileLength:   *maxTileLength,
+		strictTiles:     *strictTiles,
+		limit:           *limit,
+		tileWeighted:    *tileWeighted,
+		maxTiles:        *maxTiles,
+		anagram:         *anagram,
+		hint:            *hint,
+		decompose:       *decomposeWord,
+		seed:            *seed,
+		solution:        *solution,
+		maxSolutions:    *maxSolutions,
+		allowTileReuse:  *allowTileReuse,
+		coverage:        *coverage,
+		tileStats:       *tileStats,
+		maxScore:        *maxScore,
+		prefixCache:     *prefixCache,
+		stem:            *stem,
+		partialLastTile: *partialLastTile,
+		suggest:         *suggest,
+		frequencyPath:   *frequencyPath,
+		timeout:         *timeout,
+		lenient:         *lenient,
+		maxCandidates:   *maxCandidates,
+		dryRun:          *dryRun,
+		order:           *order,
+		format:          outputFormat,
+		scores:          scoreOverrides,
+		exportDictPath:  *exportDictPath,
+		checkWord:       *check,
+		showTiles:       *showTiles,
+		diagnostics:     stderr,
+	}
+	dictionary.apply(&opts)
+
+	// Progress lines redraw in place, so only show them on an interactive
+	// terminal and never mixed into debug, JSON, or quiet output
+	if !*common.debug && outputFormat != outputJSON && outputFormat != outputQuiet && stderr == io.Writer(os.Stderr) && isTerminal(os.Stderr) {
+		opts.progress = os.Stderr
+	}
+
+	return runCommand(ctx, opts, stdout, stderr)
+}
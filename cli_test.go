@@ -0,0 +1,90 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDispatch_Subcommands(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'castle',n,1,3).\ns(100000002,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nst\nle\ns\n")
+	dir := t.TempDir()
+	saved := logger
+	t.Cleanup(func() { logger = saved })
+	withColor(t, false)
+
+	dispatchOK := func(t *testing.T, args ...string) string {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if code := dispatch(context.Background(), args, &stdout, &stderr); code != exitFound {
+			t.Fatalf("dispatch(%v) = %d, expected %d; stderr:\n%s", args, code, exitFound, stderr.String())
+		}
+		return stdout.String()
+	}
+
+	t.Run("build-cache", func(t *testing.T) {
+		cachePath := filepath.Join(dir, "words.trie")
+		dispatchOK(t, "build-cache", "--dictionary", dictPath, "--output", cachePath)
+
+		out := dispatchOK(t, "solve", "--dictionary", cachePath, "--puzzle", puzzlePath, "--quiet")
+		if !strings.Contains(out, "castles\n") {
+			t.Errorf("Expected the cached trie to keep generated forms, got %q", out)
+		}
+	})
+
+	t.Run("export", func(t *testing.T) {
+		exportPath := filepath.Join(dir, "words.txt")
+		dispatchOK(t, "export", "--dictionary", dictPath, "--output", exportPath)
+
+		data, err := os.ReadFile(exportPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "castle\ncastles\n") {
+			t.Errorf("Expected a sorted wordlist, got %q", data)
+		}
+	})
+
+	t.Run("solve is the default", func(t *testing.T) {
+		out := dispatchOK(t, "--dictionary", dictPath, "--puzzle", puzzlePath, "--quiet")
+		if !strings.Contains(out, "castle\n") {
+			t.Errorf("Expected flags alone to solve, got %q", out)
+		}
+	})
+
+	t.Run("validate-forms honors dictionary flags", func(t *testing.T) {
+		referencePath := writeTempFile(t, "reference.txt", "castle\ncat\n")
+		out := dispatchOK(t, "--dictionary", dictPath, "--validate-forms", referencePath)
+		if !strings.Contains(out, "2 of 2 generated forms") {
+			t.Errorf("Expected castles and cats to be flagged, got %q", out)
+		}
+
+		out = dispatchOK(t, "--dictionary", dictPath, "--validate-forms", referencePath, "--no-generate-forms")
+		if !strings.Contains(out, "0 of 0 generated forms") {
+			t.Errorf("Expected --no-generate-forms to leave nothing to audit, got %q", out)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"build-cache", "--dictionary", dictPath},
+			{"export", "--dictionary", dictPath},
+			{"slove", "--puzzle", puzzlePath},
+			{"solve", "--no-such-flag"},
+		} {
+			var stdout, stderr bytes.Buffer
+			if code := dispatch(context.Background(), args, &stdout, &stderr); code != exitError {
+				t.Errorf("dispatch(%v) = %d, expected %d", args, code, exitError)
+			}
+			if !strings.Contains(stderr.String(), "Error:") {
+				t.Errorf("dispatch(%v) wrote no error, got %q", args, stderr.String())
+			}
+		}
+	})
+}
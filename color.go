@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// ANSI color codes for terminal output
+const (
+	Reset = "\033[0m"
+	Gray  = "\033[90m"
+	Green = "\033[32m"
+	Red   = "\033[31m"
+	Cyan  = "\033[36m"
+)
+
+// colorEnabled controls whether colorf emits ANSI escape codes. main sets it
+// once from shouldUseColor before any output is written.
+var colorEnabled = true
+
+// colorf formats text and, when color is enabled, wraps it in the given ANSI
+// color. Every colored print goes through here so one switch controls them all.
+func colorf(color, format string, args ...any) string {
+	text := fmt.Sprintf(format, args...)
+	if !colorEnabled {
+		return text
+	}
+	return color + text + Reset
+}
+
+// shouldUseColor reports whether output should be colored. Flags win:
+// --no-color always disables color and --color always enables it. Otherwise
+// color is off when the NO_COLOR environment variable is set to a non-empty
+// value (see https://no-color.org) or when stdout is not a terminal, so logs
+// and pipes stay free of escape codes.
+func shouldUseColor(noColor, forceColor bool, stdout *os.File) bool {
+	if noColor {
+		return false
+	}
+	if forceColor {
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(stdout)
+}
+
+// isTerminal reports whether f is a character device such as a TTY.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
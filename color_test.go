@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// withColor sets colorEnabled for the duration of a test.
+func withColor(t *testing.T, enabled bool) {
+	t.Helper()
+	previous := colorEnabled
+	colorEnabled = enabled
+	t.Cleanup(func() { colorEnabled = previous })
+}
+
+func TestColorf(t *testing.T) {
+	withColor(t, true)
+	if got := colorf(Green, "%d words", 3); got != Green+"3 words"+Reset {
+		t.Errorf("colorf() with color = %q", got)
+	}
+
+	withColor(t, false)
+	if got := colorf(Green, "%d words", 3); got != "3 words" {
+		t.Errorf("colorf() without color = %q", got)
+	}
+}
+
+func TestShouldUseColor(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	t.Setenv("NO_COLOR", "")
+	if shouldUseColor(false, false, file) {
+		t.Error("Expected no color when stdout is a regular file")
+	}
+	if shouldUseColor(true, false, os.Stdout) {
+		t.Error("Expected --no-color to disable color")
+	}
+	if !shouldUseColor(false, true, file) {
+		t.Error("Expected --color to force color")
+	}
+	if shouldUseColor(true, true, file) {
+		t.Error("Expected --no-color to win over --color")
+	}
+}
+
+func TestShouldUseColor_NoColorEnv(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	t.Setenv("NO_COLOR", "1")
+	if shouldUseColor(false, false, file) {
+		t.Error("Expected NO_COLOR to disable color")
+	}
+	if !shouldUseColor(false, true, file) {
+		t.Error("Expected --color to take precedence over NO_COLOR")
+	}
+
+	// With NO_COLOR set, solver output carries no escape sequences
+	withColor(t, shouldUseColor(false, false, file))
+	var buf bytes.Buffer
+	trie := NewTrieNode()
+	trie.Insert("cat")
+	solvePuzzle(context.Background(), trie, []string{"c", "at"}, options{}, &buf)
+	if buf.String() != " 1. cat\n" {
+		t.Errorf("Expected plain output with NO_COLOR set, got %q", buf.String())
+	}
+}
+
+func TestRunWithOptions_NoColor(t *testing.T) {
+	withColor(t, false)
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\n")
+
+	var buf bytes.Buffer
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}}
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), " 1. cat\n") {
+		t.Errorf("Expected plain numbered result, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("Expected no ANSI escape sequences, got %q", buf.String())
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// applyConfig reads a JSON config file whose keys are flag names, such as
+//
+//	{"dictionary": "./prolog/wn_s.pl", "format": "json", "scores": "4=10"}
+//
+// and sets each flag that was not given explicitly on the command line, so
+// explicit flags always win. Values may be strings, numbers, or booleans; an
+// array sets a repeatable flag such as puzzle once per element.
+func applyConfig(flags *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	explicit := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	// Apply in name order so errors are reported deterministically
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if flags.Lookup(name) == nil {
+			return fmt.Errorf("config file %s: unknown option %q", path, name)
+		}
+		if explicit[name] {
+			continue
+		}
+
+		items, isList := values[name].([]any)
+		if !isList {
+			items = []any{values[name]}
+		}
+		for _, item := range items {
+			text, err := configValueString(item)
+			if err != nil {
+				return fmt.Errorf("config file %s: option %q: %w", path, name, err)
+			}
+			if err := flags.Set(name, text); err != nil {
+				return fmt.Errorf("config file %s: option %q: %w", path, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// configValueString converts a decoded JSON value to the text a flag parses.
+func configValueString(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported value %v", value)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newConfigFlagSet returns a flag set with a few of the solver's flags.
+func newConfigFlagSet() (*flag.FlagSet, *string, *string, *bool, *time.Duration, *stringList) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	dictionary := fs.String("dictionary", "", "")
+	format := fs.String("format", outputText, "")
+	stats := fs.Bool("stats", false, "")
+	timeout := fs.Duration("timeout", 0, "")
+	var puzzles stringList
+	fs.Var(&puzzles, "puzzle", "")
+	return fs, dictionary, format, stats, timeout, &puzzles
+}
+
+func TestApplyConfig_UsesConfigWhenFlagsAbsent(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{
+		"dictionary": "/words/wn_s.pl",
+		"format": "json",
+		"stats": true,
+		"timeout": "2s",
+		"puzzle": ["a.txt", "b.txt"]
+	}`)
+
+	fs, dictionary, format, stats, timeout, puzzles := newConfigFlagSet()
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfig(fs, path); err != nil {
+		t.Fatalf("applyConfig() error = %v", err)
+	}
+
+	if *dictionary != "/words/wn_s.pl" || *format != outputJSON {
+		t.Errorf("Expected config dictionary and format, got %q and %q", *dictionary, *format)
+	}
+	if !*stats || *timeout != 2*time.Second {
+		t.Errorf("Expected stats and a 2s timeout, got %v and %v", *stats, *timeout)
+	}
+	if strings.Join(*puzzles, ",") != "a.txt,b.txt" {
+		t.Errorf("Expected both puzzles from the config array, got %v", *puzzles)
+	}
+}
+
+func TestApplyConfig_ExplicitFlagsWin(t *testing.T) {
+	path := writeTempFile(t, "config.json", `{"dictionary": "/words/wn_s.pl", "format": "json"}`)
+
+	fs, dictionary, format, _, _, _ := newConfigFlagSet()
+	if err := fs.Parse([]string{"--format", "csv"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfig(fs, path); err != nil {
+		t.Fatalf("applyConfig() error = %v", err)
+	}
+
+	if *format != outputCSV {
+		t.Errorf("Expected the explicit --format csv to win, got %q", *format)
+	}
+	if *dictionary != "/words/wn_s.pl" {
+		t.Errorf("Expected the config dictionary, got %q", *dictionary)
+	}
+}
+
+func TestApplyConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid JSON", `{"dictionary": `},
+		{"unknown option", `{"dictionery": "x"}`},
+		{"bad value", `{"stats": "sometimes"}`},
+		{"unsupported type", `{"dictionary": {"path": "x"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, _, _, _, _, _ := newConfigFlagSet()
+			if err := fs.Parse(nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := applyConfig(fs, writeTempFile(t, "config.json", tt.content)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	fs, _, _, _, _, _ := newConfigFlagSet()
+	if err := applyConfig(fs, "/nonexistent/config.json"); err == nil {
+		t.Error("Expected an error for a missing config file")
+	}
+}
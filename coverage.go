@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// uncoveredTiles returns the tiles, in puzzle order, that are not part of any
+// match. A tile no word uses is often a transcription mistake.
+func uncoveredTiles(tiles []string, matches []Result) []string {
+	used := make(map[string]bool)
+	for _, m := range matches {
+		for _, tile := range m.Tiles {
+			used[tile] = true
+		}
+	}
+
+	var uncovered []string
+	for _, tile := range tiles {
+		if !used[tile] {
+			uncovered = append(uncovered, tile)
+		}
+	}
+	return uncovered
+}
+
+// printCoverage reports tiles that no found word uses.
+func printCoverage(w io.Writer, tiles []string, matches []Result) {
+	uncovered := uncoveredTiles(tiles, matches)
+	if len(uncovered) == 0 {
+		fmt.Fprintln(w, "Coverage: every tile is used by at least one word")
+		return
+	}
+	fmt.Fprintf(w, "Coverage: %d tile(s) unused by any word: %s\n",
+		len(uncovered), strings.Join(uncovered, " "))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUncoveredTiles(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "cater", "at"} {
+		trie.Insert(word)
+	}
+
+	// "qzx" is a deliberately bogus tile that can't form any word
+	tiles := []string{"c", "qzx", "at", "er"}
+	matches, _, _ := findMatches(context.Background(), trie, tiles, 4, false, nil)
+
+	uncovered := uncoveredTiles(tiles, matches)
+	if strings.Join(uncovered, ",") != "qzx" {
+		t.Errorf("Expected only 'qzx' to be uncovered, got %v", uncovered)
+	}
+
+	var buf bytes.Buffer
+	printCoverage(&buf, tiles, matches)
+	if !strings.Contains(buf.String(), "1 tile(s) unused by any word: qzx") {
+		t.Errorf("Expected coverage report to name 'qzx', got %q", buf.String())
+	}
+}
+
+func TestPrintCoverage_AllUsed(t *testing.T) {
+	matches := []Result{{Word: "cat", Tiles: []string{"c", "at"}}}
+
+	var buf bytes.Buffer
+	printCoverage(&buf, []string{"c", "at"}, matches)
+	if !strings.Contains(buf.String(), "every tile is used") {
+		t.Errorf("Expected full coverage message, got %q", buf.String())
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// decompose returns every sequence of up to maxTiles distinct tiles that
+// spells word, fewest tiles first. It runs the same pruned search as
+// findMatches over a trie holding only word, so any arrangement that stops
+// being a prefix of word is abandoned at once. Repeated tiles that give the
+// same sequence are reported once.
+func decompose(ctx context.Context, tiles []string, word string, maxTiles int) ([]Result, error) {
+	target := NewTrieNode()
+	target.InsertNormalized(word)
+
+	matches, _, err := findMatches(ctx, target, tiles, maxTiles, false, nil)
+	matches = slices.CompactFunc(matches, func(a, b Result) bool {
+		return slices.Equal(a.Tiles, b.Tiles)
+	})
+	return matches, err
+}
+
+// printDecomposition reports each tile sequence that builds the word, or
+// that none does. A word missing from the dictionary is flagged, since the
+// game would not accept it even when the tiles spell it.
+func printDecomposition(w io.Writer, word string, inDictionary bool, sequences []Result) {
+	word = normalizeWord(word)
+	if len(sequences) == 0 {
+		fmt.Fprintf(w, "%q cannot be built from these tiles\n", word)
+	} else {
+		fmt.Fprintf(w, "%q is built by:\n", word)
+		for _, r := range sequences {
+			fmt.Fprintf(w, "  %s\n", tileBreakdown(r.Tiles))
+		}
+	}
+	if !inDictionary {
+		fmt.Fprintf(w, "Note: %q is not in the dictionary\n", word)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDecompose(t *testing.T) {
+	tiles := []string{"ca", "st", "le", "c", "ast", "xq"}
+
+	sequences, err := decompose(context.Background(), tiles, "Castle", quartileMaxTiles)
+	if err != nil {
+		t.Fatalf("decompose() error = %v", err)
+	}
+	var got []string
+	for _, r := range sequences {
+		got = append(got, strings.Join(r.Tiles, "|"))
+	}
+	if strings.Join(got, ",") != "ca|st|le,c|ast|le" {
+		t.Errorf("decompose(castle) = %v, expected [ca|st|le c|ast|le]", got)
+	}
+
+	sequences, err = decompose(context.Background(), tiles, "cattle", quartileMaxTiles)
+	if err != nil || len(sequences) != 0 {
+		t.Errorf("decompose(cattle) = %v, %v, expected no sequences", sequences, err)
+	}
+}
+
+func TestRun_Decompose(t *testing.T) {
+	withColor(t, false)
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'castle',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nst\nle\nxq\n")
+
+	run := func(word string) (int, string) {
+		t.Helper()
+		var buf bytes.Buffer
+		opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, decompose: word, diagnostics: &bytes.Buffer{}}
+		found, err := runCountingMatches(context.Background(), opts, &buf)
+		if err != nil {
+			t.Fatalf("--decompose %s: unexpected error %v", word, err)
+		}
+		return found, buf.String()
+	}
+
+	found, out := run("castle")
+	if found != 1 || !strings.Contains(out, "  ca|st|le\n") || strings.Contains(out, "not in the dictionary") {
+		t.Errorf("--decompose castle: found %d, output:\n%s", found, out)
+	}
+
+	found, out = run("stale")
+	if found != 0 || !strings.Contains(out, `"stale" cannot be built from these tiles`) {
+		t.Errorf("--decompose stale: found %d, output:\n%s", found, out)
+	}
+}
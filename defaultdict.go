@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"strings"
+)
+
+// defaultDictionary is a compact plain wordlist of common English words,
+// loaded when no --dictionary is given so the solver works without
+// downloading WordNet. It is far smaller than WordNet, so real puzzles will
+// miss words; WordNet remains the recommended source.
+//
+//go:embed wordlists/default.txt
+var defaultDictionary string
+
+// defaultDictionaryName stands in for a path in messages about the
+// embedded dictionary.
+const defaultDictionaryName = "built-in word list"
+
+// loadDefaultDictionary loads the embedded wordlist into the trie as a plain
+// wordlist and returns the number of words loaded.
+func loadDefaultDictionary(ctx context.Context, trie *TrieNode, opts loadOptions) (int, error) {
+	return readPlainWordlist(ctx, strings.NewReader(defaultDictionary), trie, opts)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRun_DefaultDictionary(t *testing.T) {
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nt\nra\nin\n")
+
+	var buf, diagnostics bytes.Buffer
+	opts := options{puzzlePaths: []string{puzzlePath}, format: outputQuiet, diagnostics: &diagnostics}
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	words := strings.Fields(buf.String())
+	for _, want := range []string{"cat", "rain"} {
+		if !slices.Contains(words, want) {
+			t.Errorf("Expected %q from the built-in dictionary, got %v", want, words)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordNetLine matches a WordNet synset fact:
+// s(synset_id,w_num,'word',pos,sense_num,tag_count).
+// Prolog escapes an apostrophe inside the quoted word by doubling it.
+var wordNetLine = regexp.MustCompile(`s\(\d+,\d+,'((?:[^']|'')+)',([nvasr]),\d+,\d+\)\.?`)
+
+// parseWordNetLine extracts the word and part of speech from a WordNet
+// synset fact, unescaping doubled apostrophes and trimming spaces. It
+// reports false for a line that is not a fact or whose word is blank.
+func parseWordNetLine(line string) (word, partOfSpeech string, ok bool) {
+	matches := wordNetLine.FindStringSubmatch(line)
+	if len(matches) != 3 {
+		return "", "", false
+	}
+	word = strings.TrimSpace(strings.ReplaceAll(matches[1], "''", "'"))
+	if word == "" {
+		return "", "", false
+	}
+	return word, matches[2], true
+}
+
+// isCapitalized reports whether the first letter of word is uppercase.
+// It decodes the first rune so accented capitals such as É are detected.
+func isCapitalized(word string) bool {
+	first, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsUpper(first)
+}
+
+// splitPhrase splits a multi-word entry on spaces and underscores.
+func splitPhrase(phrase string) []string {
+	return strings.FieldsFunc(phrase, func(r rune) bool {
+		return r == ' ' || r == '_'
+	})
+}
+
+// loadOptions controls which dictionary entries are loaded. The zero value
+// loads everything with no debug output.
+type loadOptions struct {
+	// debug logs every line read, skipped, or rejected at debug level.
+	debug bool
+	// skipSatellites drops WordNet adjective satellites (POS "s"), which
+	// mostly repeat words already listed as head adjectives.
+	skipSatellites bool
+	// includeProper keeps capitalized entries such as place names,
+	// lowercased to match tiles, instead of skipping them as proper nouns.
+	includeProper bool
+	// splitPhrases inserts each word of a multi-word entry such as
+	// "ice cream" separately instead of the whole phrase, which no tile
+	// sequence can spell.
+	splitPhrases bool
+	// agentNouns also generates the -er agent noun of each verb
+	// (run→runner), which is less reliable than the other verb forms.
+	agentNouns bool
+	// noGeneratedForms inserts only the surface forms WordNet lists, with no
+	// plurals, verb forms, or comparatives, which the generators sometimes
+	// get wrong (run→runed).
+	noGeneratedForms bool
+	// strictProlog reads each line with parseWordNetFact, a Prolog
+	// tokenizer, instead of the more forgiving parseWordNetLine.
+	strictProlog bool
+	// skips, when set, is incremented for each entry the load leaves out.
+	skips *loadSkips
+}
+
+// loadSkips counts the dictionary entries a load left out, by reason, so a
+// run can say how much of the dictionary it could use.
+type loadSkips struct {
+	ProperNouns int // capitalized entries, without --include-proper
+	Satellites  int // WordNet adjective satellites, with --include-satellites=false
+	Possessives int // wordlist entries with an apostrophe, which no tile holds
+	Malformed   int // non-blank WordNet lines that are not a synset fact
+	// Phrases counts multi-word entries inserted whole. They are loaded,
+	// but no tile sequence can spell them, so they are reported alongside.
+	Phrases int
+}
+
+// any reports whether anything was counted.
+func (s loadSkips) any() bool {
+	return s != loadSkips{}
+}
+
+// String summarizes the counts, such as "skipped 4210 proper noun(s), 33
+// malformed line(s); kept 120 phrase(s) whole", leaving out zero counts.
+func (s loadSkips) String() string {
+	var skipped []string
+	for _, count := range []struct {
+		n     int
+		label string
+	}{
+		{s.ProperNouns, "proper noun(s)"},
+		{s.Satellites, "adjective satellite(s)"},
+		{s.Possessives, "possessive(s)"},
+		{s.Malformed, "malformed line(s)"},
+	} {
+		if count.n > 0 {
+			skipped = append(skipped, fmt.Sprintf("%d %s", count.n, count.label))
+		}
+	}
+
+	var summary string
+	if len(skipped) > 0 {
+		summary = "skipped " + strings.Join(skipped, ", ")
+	}
+	if s.Phrases > 0 {
+		if summary != "" {
+			summary += "; "
+		}
+		summary += fmt.Sprintf("kept %d phrase(s) whole", s.Phrases)
+	}
+	return summary
+}
+
+// parseLine returns the WordNet line parser these options select.
+func (o loadOptions) parseLine(line string) (word, partOfSpeech string, ok bool) {
+	if o.strictProlog {
+		return parseWordNetFact(line)
+	}
+	return parseWordNetLine(line)
+}
+
+// loadDictionary loads words from a WordNet Prolog file into the trie.
+// It parses the WordNet synset format and generates common word forms
+// (plurals for nouns, past tense, participle, and -s forms for verbs,
+// comparatives and superlatives for adjectives).
+//
+// Parameters:
+//   - ctx: cancels the load between lines
+//   - dictionaryPath: path to the WordNet Prolog dictionary file (wn_s.pl)
+//   - trie: the trie data structure to populate with words
+//   - debug: if true, logs parsing details at debug level
+//
+// Returns the number of words loaded and any error encountered. A file in
+// which no line matches the WordNet format returns ErrNotWordNet, and a
+// cancelled ctx returns the context's error.
+func loadDictionary(ctx context.Context, dictionaryPath string, trie *TrieNode, debug bool) (int, error) {
+	return loadWordNet(ctx, dictionaryPath, trie, loadOptions{debug: debug})
+}
+
+// loadDictionaryReader loads WordNet Prolog facts from r into the trie like
+// loadDictionary, for dictionaries that are not files: embedded data, a
+// network response, or an in-memory string.
+func loadDictionaryReader(ctx context.Context, r io.Reader, trie *TrieNode, debug bool) (int, error) {
+	return readWordNet(ctx, r, trie, loadOptions{debug: debug})
+}
+
+// loadWordNet loads a WordNet Prolog file into the trie like loadDictionary,
+// applying the entry filters in opts.
+func loadWordNet(ctx context.Context, dictionaryPath string, trie *TrieNode, opts loadOptions) (int, error) {
+	dictionaryFile, err := openDictionary(dictionaryPath)
+	if err != nil {
+		return 0, fmt.Errorf("opening dictionary file: %w", err)
+	}
+	defer dictionaryFile.Close()
+
+	return readWordNet(ctx, dictionaryFile, trie, opts)
+}
+
+// readWordNet loads WordNet Prolog facts from r into the trie, applying the
+// entry filters in opts.
+func readWordNet(ctx context.Context, r io.Reader, trie *TrieNode, opts loadOptions) (int, error) {
+	debug := opts.debug
+	scanner := newLineScanner(r)
+	wordCount := 0
+	parsedLines := 0
+	lineNumber := 0
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("loading stopped at line %d: %w", lineNumber, err)
+		}
+		lineNumber++
+		line := scanner.Text()
+		if debug {
+			logger.Debug("reading line", "line", lineNumber, "text", line)
+		}
+
+		word, partOfSpeech, ok := opts.parseLine(line)
+		if !ok {
+			if strings.TrimSpace(line) != "" {
+				if opts.skips != nil {
+					opts.skips.Malformed++
+				}
+			}
+			if debug {
+				logger.Debug("skipping unparsable line", "line", lineNumber, "text", line)
+			}
+			continue
+		}
+		parsedLines++
+
+		if partOfSpeech == "s" && opts.skipSatellites {
+			if opts.skips != nil {
+				opts.skips.Satellites++
+			}
+			if debug {
+				logger.Debug("skipping adjective satellite", "line", lineNumber, "word", word)
+			}
+			continue
+		}
+
+		// Skip capitalized words (proper nouns) unless asked to keep them.
+		// Kept proper nouns get no generated forms: "Paris" has no plural.
+		if isCapitalized(word) {
+			if opts.includeProper {
+				if trie.Insert(strings.ToLower(word)) {
+					wordCount++
+				}
+			} else if opts.skips != nil {
+				opts.skips.ProperNouns++
+			}
+			continue
+		}
+
+		word = strings.ToLower(word)
+
+		// Phrase tokens get no generated forms since their part of speech
+		// is not known
+		if opts.splitPhrases && strings.ContainsAny(word, " _") {
+			for _, token := range splitPhrase(word) {
+				if trie.Insert(token) {
+					wordCount++
+				}
+			}
+			continue
+		}
+
+		if opts.skips != nil && strings.ContainsAny(word, " _") {
+			opts.skips.Phrases++
+		}
+
+		// Insert the base word and its generated forms
+		for _, form := range generatedForms(word, partOfSpeech, opts) {
+			if trie.Insert(form) {
+				wordCount++
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("scanning dictionary file: %w", err)
+	}
+
+	// A file with no synset facts at all is almost certainly the wrong file
+	if parsedLines == 0 {
+		return 0, fmt.Errorf("%w: no line looks like a WordNet s(...) fact; "+
+			"for a newline-delimited wordlist use --dictionary-format plain", ErrNotWordNet)
+	}
+
+	return wordCount, nil
+}
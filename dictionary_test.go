@@ -0,0 +1,547 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGeneratePlural_Unicode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"café", "cafés"},
+		{"résumé", "résumés"},
+		{"naïvety", "naïveties"},
+		{"éy", "éys"}, // accented vowel before y keeps the y
+		{"piñata", "piñatas"},
+	}
+
+	for _, tt := range tests {
+		result := generatePlural(tt.input)
+		if result != tt.expected {
+			t.Errorf("generatePlural(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+		if !utf8.ValidString(result) {
+			t.Errorf("generatePlural(%q) produced invalid UTF-8 %q", tt.input, result)
+		}
+	}
+}
+
+func TestGenerateVerbForms_Unicode(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedPast       string
+		expectedParticiple string
+	}{
+		{"flambé", "flambéed", "flambéing"},
+		{"façade", "façaded", "façading"},
+	}
+
+	for _, tt := range tests {
+		past, participle, _, _ := generateVerbForms(tt.input)
+		if past != tt.expectedPast || participle != tt.expectedParticiple {
+			t.Errorf("generateVerbForms(%q) = (%q, %q), expected (%q, %q)",
+				tt.input, past, participle, tt.expectedPast, tt.expectedParticiple)
+		}
+	}
+}
+
+func TestLoadDictionary_AccentedCapital(t *testing.T) {
+	path := writeTempFile(t, "dict.pl", "s(100000001,1,'Émile',n,1,3).\ns(100000002,1,'éclair',n,1,3).")
+
+	trie := NewTrieNode()
+	if _, err := loadDictionary(context.Background(), path, trie, false); err != nil {
+		t.Fatalf("loadDictionary failed: %v", err)
+	}
+	if trie.Search("émile") {
+		t.Error("Expected accented proper noun to be skipped")
+	}
+	if !trie.Search("éclairs") {
+		t.Error("Expected accented lowercase noun and its plural to load")
+	}
+}
+
+func TestLoadWordNet_SkipSatellites(t *testing.T) {
+	content := "s(300000001,1,'red',a,1,0).\ns(300000002,1,'crimson',s,1,0).\ns(300000003,2,'red',s,1,0)."
+	path := writeTempFile(t, "dict.pl", content)
+
+	included := NewTrieNode()
+	count, err := loadWordNet(context.Background(), path, included, loadOptions{})
+	if err != nil {
+		t.Fatalf("loadWordNet failed: %v", err)
+	}
+	// Each adjective also adds its comparative and superlative; the red
+	// satellite repeats the head adjective, so its forms are not counted again
+	if count != 6 || !included.Search("crimson") {
+		t.Errorf("Expected satellites loaded by default, got %d words", count)
+	}
+
+	skipped := NewTrieNode()
+	count, err = loadWordNet(context.Background(), path, skipped, loadOptions{skipSatellites: true})
+	if err != nil {
+		t.Fatalf("loadWordNet failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected only the head adjective and its forms counted, got %d", count)
+	}
+	if skipped.Search("crimson") {
+		t.Error("Expected the satellite entry to be skipped")
+	}
+	if !skipped.Search("red") {
+		t.Error("Expected the head adjective to remain")
+	}
+}
+
+func TestFindMatches_UnicodeTiles(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("café")
+
+	matches, _, _ := findMatches(context.Background(), trie, []string{"fé", "ca"}, 4, false, nil)
+	if len(matches) != 1 || matches[0].Word != "café" {
+		t.Fatalf("Expected 'café' from accented tiles, got %v", matches)
+	}
+	if strings.Join(matches[0].Tiles, "|") != "ca|fé" {
+		t.Errorf("Expected tiles ca|fé, got %v", matches[0].Tiles)
+	}
+}
+
+func TestLoadDictionaryFile_IncludeProper(t *testing.T) {
+	wordNet := writeTempFile(t, "dict.pl", "s(108000001,1,'Paris',n,1,0).\ns(100000002,1,'cat',n,1,3).")
+	plain := writeTempFile(t, "words.txt", "Paris\ncat\n")
+
+	for _, path := range []string{wordNet, plain} {
+		trie := NewTrieNode()
+		if _, err := loadDictionaryFile(context.Background(), path, formatAuto, trie, loadOptions{}); err != nil {
+			t.Fatalf("loadDictionaryFile(%s) failed: %v", path, err)
+		}
+		if trie.Search("paris") {
+			t.Errorf("%s: expected the proper noun skipped by default", path)
+		}
+
+		trie = NewTrieNode()
+		if _, err := loadDictionaryFile(context.Background(), path, formatAuto, trie, loadOptions{includeProper: true}); err != nil {
+			t.Fatalf("loadDictionaryFile(%s) failed: %v", path, err)
+		}
+		if !trie.Search("paris") || !trie.Search("cat") {
+			t.Errorf("%s: expected the proper noun loaded lowercase with --include-proper", path)
+		}
+		if trie.Search("parises") || trie.Search("Paris") {
+			t.Errorf("%s: expected no plural or capitalized form for the proper noun", path)
+		}
+	}
+}
+
+func TestLoadDictionary_EscapedApostrophe(t *testing.T) {
+	content := "s(400000001,1,'o''clock',r,1,0).\ns(100000002,1,'jack-o''-lantern',n,1,0).\ns(100000003,1,'cat',n,1,3)."
+	path := writeTempFile(t, "dict.pl", content)
+
+	trie := NewTrieNode()
+	count, err := loadDictionary(context.Background(), path, trie, false)
+	if err != nil {
+		t.Fatalf("loadDictionary failed: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 words (3 entries plus 2 plurals), got %d", count)
+	}
+	for _, word := range []string{"o'clock", "jack-o'-lantern", "jack-o'-lanterns", "cat"} {
+		if !trie.Search(word) {
+			t.Errorf("Expected %q to load", word)
+		}
+	}
+	if trie.Search("o''clock") || trie.Search("o") {
+		t.Error("Expected the escape to be decoded, not kept or split")
+	}
+}
+
+func TestLoadWordNet_SplitPhrases(t *testing.T) {
+	content := "s(100000001,1,'test word',n,1,0).\ns(100000002,1,'ice_cream',n,1,0)."
+	path := writeTempFile(t, "dict.pl", content)
+
+	whole := NewTrieNode()
+	if _, err := loadWordNet(context.Background(), path, whole, loadOptions{}); err != nil {
+		t.Fatalf("loadWordNet failed: %v", err)
+	}
+	if !whole.Search("test word") || whole.Search("test") {
+		t.Error("Expected phrases inserted whole by default")
+	}
+
+	split := NewTrieNode()
+	count, err := loadWordNet(context.Background(), path, split, loadOptions{splitPhrases: true})
+	if err != nil {
+		t.Fatalf("loadWordNet failed: %v", err)
+	}
+	for _, word := range []string{"test", "word", "ice", "cream"} {
+		if !split.Search(word) {
+			t.Errorf("Expected %q searchable with --split-phrases", word)
+		}
+	}
+	if split.Search("test word") || split.Search("ice_cream") {
+		t.Error("Expected the whole phrase not to be inserted")
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 tokens counted, got %d", count)
+	}
+}
+
+func TestGenerateComparatives(t *testing.T) {
+	tests := []struct {
+		input       string
+		comparative string
+		superlative string
+		ok          bool
+	}{
+		{"big", "bigger", "biggest", true},
+		{"happy", "happier", "happiest", true},
+		{"large", "larger", "largest", true},
+		{"dry", "drier", "driest", true},
+		{"gray", "grayer", "grayest", true},
+		{"new", "newer", "newest", true},
+		{"quiet", "quieter", "quietest", true},
+		{"simple", "simpler", "simplest", true},
+		{"beautiful", "", "", false},
+		{"well-known", "", "", false},
+	}
+
+	for _, tt := range tests {
+		comparative, superlative, ok := generateComparatives(tt.input)
+		if comparative != tt.comparative || superlative != tt.superlative || ok != tt.ok {
+			t.Errorf("generateComparatives(%q) = (%q, %q, %v), expected (%q, %q, %v)",
+				tt.input, comparative, superlative, ok, tt.comparative, tt.superlative, tt.ok)
+		}
+	}
+}
+
+func TestLoadDictionary_AdjectiveForms(t *testing.T) {
+	content := "s(300000001,1,'big',a,1,0).\ns(300000002,1,'happy',s,1,0)."
+	path := writeTempFile(t, "dict.pl", content)
+
+	trie := NewTrieNode()
+	count, err := loadDictionary(context.Background(), path, trie, false)
+	if err != nil {
+		t.Fatalf("loadDictionary failed: %v", err)
+	}
+	for _, word := range []string{"big", "bigger", "biggest", "happy", "happier", "happiest"} {
+		if !trie.Search(word) {
+			t.Errorf("Expected %q to load", word)
+		}
+	}
+	if count != 6 {
+		t.Errorf("Expected 6 words, got %d", count)
+	}
+}
+
+func TestGenerateVerbForms_ThirdPersonAndAgent(t *testing.T) {
+	tests := []struct {
+		input       string
+		thirdPerson string
+		agent       string
+	}{
+		{"watch", "watches", "watcher"},
+		{"carry", "carries", "carrier"},
+		{"run", "runs", "runner"},
+		{"make", "makes", "maker"},
+		{"play", "plays", "player"},
+	}
+
+	for _, tt := range tests {
+		_, _, thirdPerson, agent := generateVerbForms(tt.input)
+		if thirdPerson != tt.thirdPerson || agent != tt.agent {
+			t.Errorf("generateVerbForms(%q) = (%q, %q), expected (%q, %q)",
+				tt.input, thirdPerson, agent, tt.thirdPerson, tt.agent)
+		}
+	}
+}
+
+func TestLoadWordNet_AgentNouns(t *testing.T) {
+	path := writeTempFile(t, "dict.pl", "s(200000001,1,'run',v,1,0).")
+
+	trie := NewTrieNode()
+	count, err := loadWordNet(context.Background(), path, trie, loadOptions{})
+	if err != nil {
+		t.Fatalf("loadWordNet failed: %v", err)
+	}
+	if !trie.Search("runs") || trie.Search("runner") || count != 4 {
+		t.Errorf("Expected run, its past, participle, and -s forms without the agent noun, got %d words", count)
+	}
+
+	trie = NewTrieNode()
+	count, err = loadWordNet(context.Background(), path, trie, loadOptions{agentNouns: true})
+	if err != nil {
+		t.Fatalf("loadWordNet failed: %v", err)
+	}
+	if !trie.Search("runner") || count != 5 {
+		t.Errorf("Expected runner with --agent-nouns, got %d words", count)
+	}
+}
+
+func TestLoadWordNet_NoGeneratedForms(t *testing.T) {
+	path := writeTempFile(t, "dict.pl", strings.Join([]string{
+		"s(100000001,1,'cat',n,1,3).",
+		"s(200000002,1,'run',v,1,0).",
+		"s(300000003,1,'big',a,1,0).",
+	}, "\n"))
+
+	trie := NewTrieNode()
+	count, err := loadWordNet(context.Background(), path, trie, loadOptions{noGeneratedForms: true, agentNouns: true})
+	if err != nil {
+		t.Fatalf("loadWordNet failed: %v", err)
+	}
+	if got := trie.WordsWithPrefix(""); count != 3 || strings.Join(got, ",") != "big,cat,run" {
+		t.Errorf("Expected only the base words, got %d words %v", count, got)
+	}
+}
+
+// cancelAfterContext reports itself cancelled once Err has been called
+// checks times, to cancel a load partway through the file.
+type cancelAfterContext struct {
+	context.Context
+	checks int
+}
+
+func (c *cancelAfterContext) Err() error {
+	if c.checks <= 0 {
+		return context.Canceled
+	}
+	c.checks--
+	return nil
+}
+
+func TestLoadDictionary_CancelMidLoad(t *testing.T) {
+	var facts, words []string
+	for i := 0; i < 100; i++ {
+		word := fmt.Sprintf("word%c%c", 'a'+i/26, 'a'+i%26)
+		facts = append(facts, fmt.Sprintf("s(%d,1,'%s',n,1,0).", 100000000+i, word))
+		words = append(words, word)
+	}
+	paths := map[string]string{
+		formatWordNet: writeTempFile(t, "dict.pl", strings.Join(facts, "\n")),
+		formatPlain:   writeTempFile(t, "words.txt", strings.Join(words, "\n")),
+	}
+
+	for format, path := range paths {
+		trie := NewTrieNode()
+		ctx := &cancelAfterContext{Context: context.Background(), checks: 10}
+		_, err := loadDictionaryFile(ctx, path, format, trie, loadOptions{})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("%s: expected context.Canceled, got %v", format, err)
+		}
+		if trie.CountPrefix("") == 0 {
+			t.Errorf("%s: expected some words loaded before the cancel", format)
+		}
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := run(ctx, dictPath, puzzlePath, false, io.Discard); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from a cancelled run, got %v", err)
+	}
+}
+
+func TestLoadDictionaryReader(t *testing.T) {
+	r := strings.NewReader("s(100000001,1,'cat',n,1,3).\ns(200000002,1,'run',v,1,0).\n")
+
+	trie := NewTrieNode()
+	count, err := loadDictionaryReader(context.Background(), r, trie, false)
+	if err != nil {
+		t.Fatalf("loadDictionaryReader() error = %v", err)
+	}
+	if count != 6 || !trie.Search("cats") || !trie.Search("runs") {
+		t.Errorf("Expected cat, run, and their forms, got %d words", count)
+	}
+
+	if _, err := loadDictionaryReader(context.Background(), strings.NewReader("cat\n"), NewTrieNode(), false); !errors.Is(err, ErrNotWordNet) {
+		t.Errorf("Expected ErrNotWordNet for a plain list, got %v", err)
+	}
+}
+
+func TestLoadDictionary_NotWordNet(t *testing.T) {
+	path := writeTempFile(t, "dict.pl", "apple\nbanana\ncherry\n")
+
+	trie := NewTrieNode()
+	_, err := loadDictionary(context.Background(), path, trie, false)
+	if !errors.Is(err, ErrNotWordNet) {
+		t.Fatalf("Expected ErrNotWordNet, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "--dictionary-format plain") {
+		t.Errorf("Expected the error to suggest the plain format, got %v", err)
+	}
+
+	// The same file loads once its real format is given
+	if _, err := loadDictionaryFile(context.Background(), path, formatPlain, trie, loadOptions{}); err != nil {
+		t.Errorf("loadDictionaryFile(plain) error = %v", err)
+	}
+}
+
+func TestParseWordNetLine(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		word         string
+		partOfSpeech string
+		ok           bool
+	}{
+		{"noun", "s(102084071,1,'dog',n,1,42).", "dog", "n", true},
+		{"verb", "s(201926311,1,'run',v,1,62).", "run", "v", true},
+		{"adjective", "s(300001740,1,'able',a,1,29).", "able", "a", true},
+		{"adjective satellite", "s(300004296,1,'eatable',s,1,0).", "eatable", "s", true},
+		{"adverb", "s(400002310,1,'quickly',r,1,22).", "quickly", "r", true},
+		{"escaped apostrophe", "s(104437670,1,'o''clock',r,1,0).", "o'clock", "r", true},
+		{"capitalized kept as is", "s(108929922,1,'Paris',n,1,9).", "Paris", "n", true},
+		{"phrase", "s(107609840,1,'ice cream',n,1,2).", "ice cream", "n", true},
+		{"no final period", "s(102084071,1,'dog',n,1,42)", "dog", "n", true},
+		{"padded word", "s(102084071,1,' dog ',n,1,42).", "dog", "n", true},
+		{"unknown part of speech", "s(102084071,1,'dog',x,1,42).", "", "", false},
+		{"empty word", "s(102084071,1,'',n,1,42).", "", "", false},
+		{"blank word", "s(100000001,1,'  ',n,1,0).", "", "", false},
+		{"missing field", "s(102084071,'dog',n,1,42).", "", "", false},
+		{"non-numeric id", "s(dog,1,'dog',n,1,42).", "", "", false},
+		{"gloss fact", "g(102084071,'a member of the genus Canis').", "", "", false},
+		{"plain word", "dog", "", "", false},
+		{"empty line", "", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			word, partOfSpeech, ok := parseWordNetLine(tt.line)
+			if word != tt.word || partOfSpeech != tt.partOfSpeech || ok != tt.ok {
+				t.Errorf("parseWordNetLine(%q) = %q, %q, %v, expected %q, %q, %v",
+					tt.line, word, partOfSpeech, ok, tt.word, tt.partOfSpeech, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLoadDictionary_BlankWord(t *testing.T) {
+	trie := NewTrieNode()
+	input := "s(100000001,1,'  ',n,1,0).\ns(100000002,1,'cat',n,1,3)."
+	count, err := loadDictionaryReader(context.Background(), strings.NewReader(input), trie, false)
+	if err != nil {
+		t.Fatalf("loadDictionaryReader() error = %v", err)
+	}
+	if count != 2 || trie.Search("") || trie.Search("s") {
+		t.Errorf("Expected only cat and cats from %q, got %v", input, trie.WordsWithPrefix(""))
+	}
+}
+
+// FuzzLoadDictionaryLine checks that the WordNet line parsers never panic
+// and that anything the default one accepts is a usable entry: a trimmed, non-blank word
+// and a known part of speech that parse back unchanged when written out as
+// a fact again. The seeds run as part of the normal test suite; use
+// go test -fuzz=FuzzLoadDictionaryLine to search further.
+func FuzzLoadDictionaryLine(f *testing.F) {
+	for _, seed := range []string{
+		"s(100001740,1,'entity',n,1,11).",
+		"s(102084071,1,'dog',n,1,42).",
+		"s(201926311,1,'run',v,1,62).",
+		"s(300001740,1,'able',a,1,29).",
+		"s(300004296,1,'eatable',s,1,0).",
+		"s(400002310,1,'quickly',r,1,22).",
+		"s(104437670,1,'o''clock',r,1,0).",
+		"s(108929922,1,'Paris',n,1,9).",
+		"s(107609840,1,'ice_cream',n,1,2).",
+		"s(100001740,1,'entity',n,1,11)",
+		"s(100001740,1,'entity',x,1,11).",
+		"s(100001740,1,'',n,1,11).",
+		"s(1,1,' ',n,1,1).",
+		"s(1,1,''''''',n,1,1).",
+		"g(100001740,'that which is perceived').",
+		"",
+		"\xff\xfe",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		// The --strict-prolog parser must not panic either, and a fact it
+		// accepts has a usable word
+		if word, partOfSpeech, ok := parseWordNetFact(line); ok && (word == "" || word != strings.TrimSpace(word) || !strings.Contains("nvasr", partOfSpeech)) {
+			t.Errorf("parseWordNetFact(%q) accepted %q, %q", line, word, partOfSpeech)
+		}
+
+		word, partOfSpeech, ok := parseWordNetLine(line)
+		if !ok {
+			if word != "" || partOfSpeech != "" {
+				t.Errorf("parseWordNetLine(%q) rejected the line but returned %q, %q", line, word, partOfSpeech)
+			}
+			return
+		}
+		if word == "" || word != strings.TrimSpace(word) {
+			t.Errorf("parseWordNetLine(%q) accepted untrimmed or blank word %q", line, word)
+		}
+		if len(partOfSpeech) != 1 || !strings.Contains("nvasr", partOfSpeech) {
+			t.Errorf("parseWordNetLine(%q) accepted part of speech %q", line, partOfSpeech)
+		}
+
+		fact := fmt.Sprintf("s(100000001,1,'%s',%s,1,0).", strings.ReplaceAll(word, "'", "''"), partOfSpeech)
+		again, againPOS, ok := parseWordNetLine(fact)
+		if !ok || again != word || againPOS != partOfSpeech {
+			t.Errorf("parseWordNetLine(%q) = %q, %q, which rewritten as %q parses as %q, %q, %v", line, word, partOfSpeech, fact, again, againPOS, ok)
+		}
+
+		// The loader itself must not panic on whatever the parser accepted
+		if _, err := loadDictionaryReader(context.Background(), strings.NewReader(line), NewTrieNode(), false); err != nil && !errors.Is(err, ErrNotWordNet) {
+			t.Errorf("loadDictionaryReader(%q) error = %v", line, err)
+		}
+	})
+}
+
+func TestReadWordNet_SkipCounts(t *testing.T) {
+	dictionary := strings.Join([]string{
+		"s(102084071,1,'dog',n,1,42).",
+		"s(108929922,1,'Paris',n,1,9).",
+		"s(109505418,1,'Zeus',n,1,0).",
+		"s(300004296,1,'eatable',s,1,0).",
+		"s(107609840,1,'ice_cream',n,1,2).",
+		"g(102084071,'a member of the genus Canis').",
+		"not a fact",
+		"",
+	}, "\n")
+
+	var skips loadSkips
+	if _, err := readWordNet(context.Background(), strings.NewReader(dictionary), NewTrieNode(), loadOptions{skipSatellites: true, skips: &skips}); err != nil {
+		t.Fatalf("readWordNet() error = %v", err)
+	}
+	want := loadSkips{ProperNouns: 2, Satellites: 1, Malformed: 2, Phrases: 1}
+	if skips != want {
+		t.Errorf("Expected skip counts %+v, got %+v", want, skips)
+	}
+	if got, summary := skips.String(), "skipped 2 proper noun(s), 1 adjective satellite(s), 2 malformed line(s); kept 1 phrase(s) whole"; got != summary {
+		t.Errorf("String() = %q, expected %q", got, summary)
+	}
+}
+
+func TestRunWithOptions_ReportsSkips(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "castle\nParis\ncastle's\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nst\nle\n")
+
+	var diagnostics bytes.Buffer
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, diagnostics: &diagnostics}
+	if err := runWithOptions(context.Background(), opts, io.Discard); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	if want := "Dictionary: skipped 1 proper noun(s), 1 possessive(s)"; !strings.Contains(diagnostics.String(), want) {
+		t.Errorf("Expected %q in notices, got %q", want, diagnostics.String())
+	}
+}
+
+func TestReadPlainWordlist_CountsDistinctWords(t *testing.T) {
+	trie := NewTrieNode()
+	count, err := readPlainWordlist(context.Background(), strings.NewReader("castle\ncastle\nCastle\nmoat\n"), trie, loadOptions{includeProper: true})
+	if err != nil {
+		t.Fatalf("readPlainWordlist() error = %v", err)
+	}
+	if count != 2 || count != trie.WordCount() {
+		t.Errorf("Expected 2 distinct words counted, got %d (trie holds %d)", count, trie.WordCount())
+	}
+}
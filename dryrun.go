@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// printDryRun reports the size of the search a solve would run without
+// enumerating any tile arrangements. It gives a cheap way to catch a
+// mistyped puzzle or a --max-tiles value that would run for too long.
+func printDryRun(w io.Writer, trie wordTrie, tiles []string, wordCount int, opts options) {
+	maxTiles := opts.tileLimit()
+	projected := projectCandidates(len(tiles), maxTiles)
+
+	startsWord := 0
+	for _, tile := range tiles {
+		if trie.HasPrefix(tile) {
+			startsWord++
+		}
+	}
+
+	fmt.Fprintln(w, "Dry run (no words solved):")
+	fmt.Fprintf(w, "  Dictionary words:     %d\n", wordCount)
+	fmt.Fprintf(w, "  Tiles:                %d\n", len(tiles))
+	fmt.Fprintf(w, "  Max tiles per word:   %d\n", maxTiles)
+	fmt.Fprintf(w, "  Projected candidates: %d (upper bound before prefix pruning)\n", projected)
+	fmt.Fprintf(w, "  Tiles starting words: %d of %d\n", startsWord, len(tiles))
+
+	switch {
+	case opts.maxCandidates <= 0:
+		fmt.Fprintln(w, "  Candidate limit:      disabled")
+	case projected > opts.maxCandidates:
+		fmt.Fprintf(w, "  Candidate limit:      %d, exceeded; a solve would be refused\n", opts.maxCandidates)
+	default:
+		fmt.Fprintf(w, "  Candidate limit:      %d, within limit\n", opts.maxCandidates)
+	}
+}
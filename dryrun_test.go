@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRun_DryRun(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\nxq\n")
+
+	var buf bytes.Buffer
+	err := runWithOptions(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		dryRun:         true,
+		maxCandidates:  defaultMaxCandidates,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"Dry run (no words solved):",
+		"Tiles:                3",
+		"Projected candidates: 15",
+		"Tiles starting words: 1 of 3",
+		"within limit",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, " cat") || strings.Contains(output, "cats") {
+		t.Errorf("Expected no solved words in a dry run, got:\n%s", output)
+	}
+}
+
+func TestRun_DryRunReportsExceededLimit(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\nxq\n")
+
+	var buf bytes.Buffer
+	err := runWithOptions(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		dryRun:         true,
+		maxCandidates:  10,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("Expected a dry run to report rather than fail, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "exceeded") {
+		t.Errorf("Expected the exceeded limit to be reported, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+// Process exit codes. A solve that finds nothing is not an error, but it
+// gets its own code so shell pipelines can branch on it.
+const (
+	exitFound   = 0
+	exitError   = 1
+	exitNoWords = 2
+)
+
+// exitCode maps the outcome of a run to the process exit code: exitError
+// for any failure, exitNoWords when a solve found no words, and exitFound
+// otherwise. Interactive, dry, and export-only runs solve nothing up front,
+// so they exit with exitFound whenever they succeed.
+func exitCode(opts options, found int, err error) int {
+	switch {
+	case err != nil:
+		return exitError
+	case opts.interactive || opts.dryRun || opts.exportOnly():
+		return exitFound
+	case found == 0:
+		return exitNoWords
+	default:
+		return exitFound
+	}
+}
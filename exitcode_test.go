@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRunCountingMatches_ExitCode(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	solvable := writeTempFile(t, "solvable.txt", "c\nat\n")
+	unsolvable := writeTempFile(t, "unsolvable.txt", "xq\nzz\n")
+
+	tests := []struct {
+		name      string
+		puzzles   []string
+		wantFound int
+		wantExit  int
+	}{
+		{"match", []string{solvable}, 1, exitFound},
+		{"no match", []string{unsolvable}, 0, exitNoWords},
+		{"any puzzle matches", []string{unsolvable, solvable}, 1, exitFound},
+	}
+
+	for _, tt := range tests {
+		opts := options{
+			dictionaryPath: dictPath,
+			puzzlePaths:    tt.puzzles,
+			maxCandidates:  defaultMaxCandidates,
+		}
+		found, err := runCountingMatches(context.Background(), opts, io.Discard)
+		if err != nil {
+			t.Fatalf("%s: runCountingMatches() error = %v", tt.name, err)
+		}
+		if found != tt.wantFound {
+			t.Errorf("%s: found %d words, expected %d", tt.name, found, tt.wantFound)
+		}
+		if code := exitCode(opts, found, err); code != tt.wantExit {
+			t.Errorf("%s: exitCode() = %d, expected %d", tt.name, code, tt.wantExit)
+		}
+	}
+}
+
+func TestExitCode_ErrorsAndDryRun(t *testing.T) {
+	if code := exitCode(options{}, 3, errors.New("boom")); code != exitError {
+		t.Errorf("Expected an error to exit %d, got %d", exitError, code)
+	}
+	if code := exitCode(options{dryRun: true}, 0, nil); code != exitFound {
+		t.Errorf("Expected a successful dry run to exit %d, got %d", exitFound, code)
+	}
+	if code := exitCode(options{interactive: true}, 0, nil); code != exitFound {
+		t.Errorf("Expected a successful interactive session to exit %d, got %d", exitFound, code)
+	}
+	if code := exitCode(options{exportDictPath: "words.txt"}, 0, nil); code != exitFound {
+		t.Errorf("Expected a successful export-only run to exit %d, got %d", exitFound, code)
+	}
+}
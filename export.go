@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// exportDictionary writes every word in the trie to exportPath, one per
+// line in sorted order, and returns how many were written. The file is a
+// plain wordlist, so it can be loaded again with --dictionary-format plain
+// without parsing WordNet or generating forms.
+func exportDictionary(trie wordTrie, exportPath string) (int, error) {
+	file, err := os.Create(exportPath)
+	if err != nil {
+		return 0, fmt.Errorf("creating dictionary export: %w", err)
+	}
+
+	buffered := bufio.NewWriter(file)
+	count := 0
+	var writeErr error
+	trie.Walk(func(word string) {
+		if writeErr != nil {
+			return
+		}
+		if _, writeErr = buffered.WriteString(word + "\n"); writeErr == nil {
+			count++
+		}
+	})
+	if writeErr == nil {
+		writeErr = buffered.Flush()
+	}
+	if closeErr := file.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		return 0, fmt.Errorf("writing dictionary export %s: %w", exportPath, writeErr)
+	}
+	return count, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExportDictionary_RoundTrip(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).\ns(100000002,1,'ant',n,1,3).")
+	exportPath := filepath.Join(t.TempDir(), "export.txt")
+
+	var buf bytes.Buffer
+	opts := options{dictionaryPath: dictPath, exportDictPath: exportPath, format: outputQuiet}
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for an export-only quiet run, got %q", buf.String())
+	}
+
+	exported, err := readWordList(exportPath)
+	if err != nil {
+		t.Fatalf("readWordList() error = %v", err)
+	}
+	want := []string{"ant", "ants", "cat", "cats"}
+	if !reflect.DeepEqual(exported, want) {
+		t.Errorf("Exported %v, expected %v", exported, want)
+	}
+
+	// Loading the export as a plain wordlist gives back the same lexicon
+	trie := NewTrieNode()
+	count, err := loadPlainWordlist(context.Background(), exportPath, trie, loadOptions{})
+	if err != nil {
+		t.Fatalf("loadPlainWordlist() error = %v", err)
+	}
+	if count != len(want) {
+		t.Errorf("Expected %d words reloaded, got %d", len(want), count)
+	}
+	for _, word := range want {
+		if !trie.Search(word) {
+			t.Errorf("Expected %q in the reloaded dictionary", word)
+		}
+	}
+}
+
+func TestExportDictionary_BadPath(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+	if _, err := exportDictionary(trie, filepath.Join(t.TempDir(), "missing", "export.txt")); err == nil {
+		t.Error("Expected an error exporting to a missing directory")
+	}
+}
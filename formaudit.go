@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// formAudit is a generated form that a reference word list does not contain.
+type formAudit struct {
+	Form         string
+	Base         string
+	PartOfSpeech string
+}
+
+// auditGeneratedForms reads a WordNet Prolog file and checks every form the
+// loader would generate (not the base words themselves) against the words in
+// the reference list. It returns the forms missing from the reference, in
+// file order and each listed once, along with how many distinct forms were
+// checked. Proper nouns and phrases are skipped since they get no forms.
+func auditGeneratedForms(dictionaryPath, referencePath string, opts loadOptions) ([]formAudit, int, error) {
+	words, err := readWordList(referencePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	reference := make(map[string]bool, len(words))
+	for _, word := range words {
+		reference[word] = true
+	}
+
+	dictionaryFile, err := openDictionary(dictionaryPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening dictionary file: %w", err)
+	}
+	defer dictionaryFile.Close()
+
+	var missing []formAudit
+	checked := make(map[string]bool)
+	scanner := newLineScanner(dictionaryFile)
+	for scanner.Scan() {
+		word, partOfSpeech, ok := opts.parseLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		if isCapitalized(word) || strings.ContainsAny(word, " _") {
+			continue
+		}
+		if partOfSpeech == "s" && opts.skipSatellites {
+			continue
+		}
+
+		word = strings.ToLower(word)
+		for _, form := range generatedForms(word, partOfSpeech, opts)[1:] {
+			if form == word || checked[form] {
+				continue
+			}
+			checked[form] = true
+			if !reference[form] {
+				missing = append(missing, formAudit{Form: form, Base: word, PartOfSpeech: partOfSpeech})
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("scanning dictionary file: %w", err)
+	}
+	return missing, len(checked), nil
+}
+
+// runFormAudit audits the forms generated from a WordNet dictionary against
+// the reference list at referencePath and prints the report to w.
+func runFormAudit(dictionaryPath, referencePath string, opts loadOptions, w io.Writer) error {
+	if dictionaryPath == "" {
+		return errors.New("--validate-forms requires --dictionary")
+	}
+	missing, checked, err := auditGeneratedForms(dictionaryPath, referencePath, opts)
+	if err != nil {
+		return err
+	}
+	printFormAudit(w, missing, checked)
+	return nil
+}
+
+// printFormAudit lists each generated form missing from the reference list
+// with the base word it came from, then a summary of how many were flagged.
+func printFormAudit(w io.Writer, missing []formAudit, checked int) {
+	for _, audit := range missing {
+		fmt.Fprintf(w, "%s  (from %s, %s)\n", audit.Form, audit.Base, audit.PartOfSpeech)
+	}
+
+	percent := 0.0
+	if checked > 0 {
+		percent = 100 * float64(len(missing)) / float64(checked)
+	}
+	fmt.Fprintf(w, "%d of %d generated forms (%.1f%%) are not in the reference list\n", len(missing), checked, percent)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAuditGeneratedForms(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", strings.Join([]string{
+		"s(200000001,1,'run',v,1,0).",
+		"s(200000002,1,'run',v,2,0).",
+		"s(100000003,1,'cat',n,1,3).",
+		"s(108000004,1,'Paris',n,1,0).",
+	}, "\n"))
+	referencePath := writeTempFile(t, "reference.txt", "run\nran\nrunning\nruns\ncat\ncats\n")
+
+	missing, checked, err := auditGeneratedForms(dictPath, referencePath, loadOptions{})
+	if err != nil {
+		t.Fatalf("auditGeneratedForms() error = %v", err)
+	}
+
+	// run generates runed, runing, and runs; cat generates cats
+	want := []formAudit{
+		{Form: "runed", Base: "run", PartOfSpeech: "v"},
+		{Form: "runing", Base: "run", PartOfSpeech: "v"},
+	}
+	if !reflect.DeepEqual(missing, want) {
+		t.Errorf("Flagged %+v, expected %+v", missing, want)
+	}
+	if checked != 4 {
+		t.Errorf("Expected 4 distinct forms checked, got %d", checked)
+	}
+
+	var buf bytes.Buffer
+	printFormAudit(&buf, missing, checked)
+	if !strings.Contains(buf.String(), "runed  (from run, v)") || !strings.Contains(buf.String(), "2 of 4 generated forms (50.0%)") {
+		t.Errorf("Unexpected audit report %q", buf.String())
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// GeneratedForms returns exactly the words the WordNet loader inserts for a
+// lowercase base word with the given part of speech (n, v, a, s, or r) under
+// the default options: the base itself followed by its generated forms. It
+// is meant for auditing the form generators without loading a dictionary.
+func GeneratedForms(base, pos string) []string {
+	return generatedForms(base, pos, loadOptions{})
+}
+
+// generatedForms returns the base word followed by the forms generated for
+// its part of speech, honoring the form options in opts.
+func generatedForms(word, partOfSpeech string, opts loadOptions) []string {
+	forms := []string{word}
+	if opts.noGeneratedForms {
+		return forms
+	}
+
+	switch partOfSpeech {
+	case "n":
+		forms = append(forms, generatePlural(word))
+	case "v":
+		past, participle, thirdPerson, agent := generateVerbForms(word)
+		forms = append(forms, past, participle, thirdPerson)
+		if opts.agentNouns {
+			forms = append(forms, agent)
+		}
+	case "a", "s":
+		if comparative, superlative, ok := generateComparatives(word); ok {
+			forms = append(forms, comparative, superlative)
+		}
+	}
+	return forms
+}
+
+// pluralOTakesS lists nouns ending in a consonant and o that take a plain
+// -s, mostly clipped or borrowed words (photo, piano), unlike hero→heroes.
+var pluralOTakesS = map[string]bool{
+	"auto": true, "canto": true, "combo": true, "demo": true, "disco": true,
+	"dynamo": true, "ego": true, "euro": true, "halo": true, "hippo": true,
+	"kilo": true, "kimono": true, "limo": true, "logo": true, "memo": true,
+	"photo": true, "piano": true, "pro": true, "silo": true, "solo": true,
+	"soprano": true, "taco": true, "typo": true, "tuxedo": true,
+}
+
+// pluralFTakesVes lists the nouns ending in f or fe whose plural drops it
+// for -ves (leaf→leaves, knife→knives). It is a closed set in English:
+// most such nouns take a plain -s (roof→roofs, chief→chiefs, golf→golfs),
+// so -ves is the exception and is never guessed for a noun not listed here.
+var pluralFTakesVes = map[string]bool{
+	"bookshelf": true, "calf": true, "elf": true, "half": true, "hoof": true,
+	"housewife": true, "jackknife": true, "knife": true, "leaf": true,
+	"life": true, "loaf": true, "meatloaf": true, "midwife": true,
+	"penknife": true, "scarf": true, "self": true, "sheaf": true,
+	"shelf": true, "thief": true, "werewolf": true, "wharf": true,
+	"wife": true, "wolf": true,
+}
+
+// generatePlural generates the plural form of a noun using basic English
+// rules. Nouns listed in pluralFTakesVes take -ves (leaf→leaves,
+// knife→knives); everything else follows appendS.
+func generatePlural(word string) string {
+	if stem, ok := vesStem(word); ok {
+		return stem + "ves"
+	}
+	return appendS(word)
+}
+
+// vesStem returns the stem a -ves plural attaches to, such as "lea" for
+// leaf or "kni" for knife. ok is false for nouns not in pluralFTakesVes.
+func vesStem(word string) (stem string, ok bool) {
+	if !pluralFTakesVes[word] {
+		return "", false
+	}
+	if stem, found := strings.CutSuffix(word, "fe"); found {
+		return stem, true
+	}
+	return strings.TrimSuffix(word, "f"), true
+}
+
+// appendS adds the -s ending shared by plurals and third-person verbs:
+// -es after a sibilant (box→boxes) or a consonant and o (hero→heroes), -ies
+// for a consonant and y (city→cities), and -s otherwise.
+func appendS(word string) string {
+	if strings.HasSuffix(word, "s") || strings.HasSuffix(word, "sh") ||
+		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "x") ||
+		strings.HasSuffix(word, "z") {
+		return word + "es"
+	}
+	runes := []rune(word)
+	if strings.HasSuffix(word, "y") && len(runes) > 1 && !isVowel(runes[len(runes)-2]) {
+		return string(runes[:len(runes)-1]) + "ies"
+	}
+	// A consonant before the o takes -es (hero→heroes), a vowel -s (radio→radios)
+	if strings.HasSuffix(word, "o") && len(runes) > 1 && !isVowel(runes[len(runes)-2]) && !pluralOTakesS[word] {
+		return word + "es"
+	}
+	return word + "s"
+}
+
+// isVowel reports whether r is a vowel, including accented forms such as é.
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiouàáâäèéêëìíîïòóôöùúûü", unicode.ToLower(r))
+}
+
+// generateVerbForms generates the past tense, present participle,
+// third-person singular, and agent noun forms of a verb. The third-person
+// form takes the -s ending of appendS (watch→watches, carry→carries) and the agent
+// noun the -er rules (run→runner, make→maker).
+func generateVerbForms(word string) (past, participle, thirdPerson, agent string) {
+	// Past tense
+	if strings.HasSuffix(word, "e") {
+		past = word + "d"
+	} else {
+		past = word + "ed"
+	}
+
+	// Present participle
+	runes := []rune(word)
+	if strings.HasSuffix(word, "e") && len(runes) > 1 {
+		participle = string(runes[:len(runes)-1]) + "ing"
+	} else {
+		participle = word + "ing"
+	}
+
+	thirdPerson = appendS(word)
+	agent = erStem([]rune(word)) + "er"
+
+	return past, participle, thirdPerson, agent
+}
+
+// generateComparatives generates the comparative and superlative forms of an
+// adjective: big→bigger/biggest, happy→happier/happiest, large→larger/largest.
+// ok is false for adjectives of three or more syllables and for phrases,
+// which take "more" and "most" in English instead.
+func generateComparatives(word string) (comparative, superlative string, ok bool) {
+	runes := []rune(word)
+	if len(runes) < 2 || strings.ContainsAny(word, " _-'") || syllableCount(runes) > 2 {
+		return "", "", false
+	}
+
+	stem := erStem(runes)
+	return stem + "er", stem + "est", true
+}
+
+// erStem returns the stem that -er and -est attach to: a final e is dropped
+// (large→larg), a y after a consonant becomes i (happy→happi), and the final
+// consonant of a one-syllable consonant-vowel-consonant word is doubled
+// (big→bigg).
+func erStem(runes []rune) string {
+	word := string(runes)
+	if len(runes) < 2 {
+		return word
+	}
+
+	last, beforeLast := runes[len(runes)-1], runes[len(runes)-2]
+	switch {
+	case last == 'e':
+		return string(runes[:len(runes)-1])
+	case last == 'y' && !isVowel(beforeLast):
+		return string(runes[:len(runes)-1]) + "i"
+	case syllableCount(runes) == 1 && endsConsonantVowelConsonant(runes):
+		return word + string(last)
+	}
+	return word
+}
+
+// syllableCount estimates syllables as the number of vowel groups, counting
+// y as a vowel anywhere but the start of the word.
+func syllableCount(runes []rune) int {
+	count := 0
+	inVowels := false
+	for i, r := range runes {
+		vowel := isVowel(r) || (r == 'y' && i > 0)
+		if vowel && !inVowels {
+			count++
+		}
+		inVowels = vowel
+	}
+	return count
+}
+
+// endsConsonantVowelConsonant reports whether a word ends in a single vowel
+// between consonants, where English doubles the final consonant before a
+// suffix (big→bigger). Final w, x, and y are never doubled.
+func endsConsonantVowelConsonant(runes []rune) bool {
+	n := len(runes)
+	if n < 3 {
+		return false
+	}
+	last := runes[n-1]
+	if isVowel(last) || strings.ContainsRune("wxy", last) {
+		return false
+	}
+	return isVowel(runes[n-2]) && !isVowel(runes[n-3])
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGeneratedForms(t *testing.T) {
+	tests := []struct {
+		base string
+		pos  string
+		want []string
+	}{
+		{"box", "n", []string{"box", "boxes"}},
+		{"city", "n", []string{"city", "cities"}},
+		{"watch", "v", []string{"watch", "watched", "watching", "watches"}},
+		{"bake", "v", []string{"bake", "baked", "baking", "bakes"}},
+		{"knife", "n", []string{"knife", "knives"}},
+		{"knife", "v", []string{"knife", "knifed", "knifing", "knifes"}},
+		{"big", "a", []string{"big", "bigger", "biggest"}},
+		{"quickly", "r", []string{"quickly"}},
+	}
+
+	for _, tt := range tests {
+		if got := GeneratedForms(tt.base, tt.pos); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GeneratedForms(%q, %q) = %v, expected %v", tt.base, tt.pos, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratedForms_MatchesLoader(t *testing.T) {
+	path := writeTempFile(t, "dict.pl", "s(200000001,1,'carry',v,1,0).")
+
+	trie := NewTrieNode()
+	if _, err := loadDictionary(context.Background(), path, trie, false); err != nil {
+		t.Fatalf("loadDictionary failed: %v", err)
+	}
+
+	forms := GeneratedForms("carry", "v")
+	if got := trie.WordsWithPrefix(""); len(got) != len(forms) {
+		t.Errorf("Loader inserted %v, GeneratedForms reported %v", got, forms)
+	}
+	for _, form := range forms {
+		if !trie.Search(form) {
+			t.Errorf("GeneratedForms reported %q but the loader did not insert it", form)
+		}
+	}
+}
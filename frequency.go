@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// frequencyTable maps words to how commonly they are used. Higher counts
+// are more common; words missing from the table count as 0, the rarest.
+type frequencyTable map[string]int
+
+// readFrequencyList reads a frequency file for --frequency. Each line holds
+// a word and its usage count separated by whitespace, such as "the 23135851".
+// A line with only a word ranks it by position instead, so a plain list
+// sorted most common first also works. Blank lines and lines starting with
+// # are ignored.
+func readFrequencyList(path string) (frequencyTable, error) {
+	listFile, err := openDictionary(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening frequency list: %w", err)
+	}
+	defer listFile.Close()
+
+	table := make(frequencyTable)
+	var ranked []string
+	lineNumber := 0
+	scanner := newLineScanner(listFile)
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		word := strings.ToLower(fields[0])
+		switch len(fields) {
+		case 1:
+			ranked = append(ranked, word)
+		case 2:
+			count, err := strconv.Atoi(fields[1])
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("frequency list %s line %d: invalid count %q", path, lineNumber, fields[1])
+			}
+			table[word] = count
+		default:
+			return nil, fmt.Errorf("frequency list %s line %d: expected a word and an optional count", path, lineNumber)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading frequency list %s: %w", path, err)
+	}
+
+	// The first ranked word gets the highest count
+	for i, word := range ranked {
+		if _, ok := table[word]; !ok {
+			table[word] = len(ranked) - i
+		}
+	}
+	return table, nil
+}
+
+// sortMatchesByFrequency orders matches like sortMatches, fewest tiles
+// first, but among words with the same tile count lists the most frequent
+// first. Words with equal frequency fall back to alphabetical and then tile
+// order, so the result is still deterministic.
+func sortMatchesByFrequency(matches []Result, frequencies frequencyTable) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if len(a.Tiles) != len(b.Tiles) {
+			return len(a.Tiles) < len(b.Tiles)
+		}
+		if fa, fb := frequencies[a.Word], frequencies[b.Word]; fa != fb {
+			return fa > fb
+		}
+		if a.Word != b.Word {
+			return a.Word < b.Word
+		}
+		return strings.Join(a.Tiles, "|") < strings.Join(b.Tiles, "|")
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReadFrequencyList(t *testing.T) {
+	path := writeTempFile(t, "freq.txt", "# word count\nThe 5000\ncat 120\n\nquixotic 2\n")
+	table, err := readFrequencyList(path)
+	if err != nil {
+		t.Fatalf("readFrequencyList() error = %v", err)
+	}
+	if table["the"] != 5000 || table["cat"] != 120 || table["quixotic"] != 2 {
+		t.Errorf("Unexpected counts %v", table)
+	}
+	if table["missing"] != 0 {
+		t.Error("Expected an unlisted word to count as 0")
+	}
+}
+
+func TestReadFrequencyList_RankedWords(t *testing.T) {
+	path := writeTempFile(t, "freq.txt", "the\ncat\nquixotic\n")
+	table, err := readFrequencyList(path)
+	if err != nil {
+		t.Fatalf("readFrequencyList() error = %v", err)
+	}
+	if !(table["the"] > table["cat"] && table["cat"] > table["quixotic"] && table["quixotic"] > 0) {
+		t.Errorf("Expected earlier words to rank higher, got %v", table)
+	}
+}
+
+func TestReadFrequencyList_InvalidCount(t *testing.T) {
+	path := writeTempFile(t, "freq.txt", "cat 12\ndog many\n")
+	_, err := readFrequencyList(path)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error naming line 2, got %v", err)
+	}
+}
+
+func TestRun_OrderFrequency(t *testing.T) {
+	// "acter" sorts first alphabetically but is missing from the list
+	dictPath := writeTempFile(t, "words.txt", "acter\ncater\n")
+	freqPath := writeTempFile(t, "freq.txt", "cater 900\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ac\nca\nter\n")
+
+	var buf bytes.Buffer
+	err := runWithOptions(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		frequencyPath:  freqPath,
+		order:          orderFrequency,
+		format:         outputQuiet,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+
+	if got := strings.Fields(buf.String()); strings.Join(got, ",") != "cater,acter" {
+		t.Errorf("Expected the common word first, got %v", got)
+	}
+}
+
+func TestSortMatchesByFrequency(t *testing.T) {
+	matches := []Result{
+		{Word: "abed", Tiles: []string{"ab", "ed"}},
+		{Word: "used", Tiles: []string{"us", "ed"}},
+		{Word: "a", Tiles: []string{"a"}},
+	}
+	sortMatchesByFrequency(matches, frequencyTable{"used": 800, "abed": 3})
+
+	var words []string
+	for _, m := range matches {
+		words = append(words, m.Word)
+	}
+	if got := strings.Join(words, ","); got != "a,used,abed" {
+		t.Errorf("Expected fewer tiles first, then the common word, got %s", got)
+	}
+}
+
+func TestRun_OrderFrequencyRequiresList(t *testing.T) {
+	err := runWithOptions(context.Background(), options{order: orderFrequency}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "--frequency") {
+		t.Errorf("Expected an error asking for --frequency, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import "sort"
+
+// FuzzySearch returns every word in the trie within maxDist Levenshtein edits
+// of word, sorted alphabetically. A maxDist of 0 is an exact lookup.
+//
+// It walks the trie once, carrying one row of the edit-distance table per
+// node, and skips any branch whose row has no entry within maxDist since
+// extending it can only increase the distance.
+func (t *TrieNode) FuzzySearch(word string, maxDist int) []string {
+	return fuzzySearch(t, word, maxDist)
+}
+
+// fuzzySearch is FuzzySearch over the words below node, returning the
+// letters that follow it.
+func fuzzySearch(node trieCursor, word string, maxDist int) []string {
+	if maxDist < 0 {
+		return nil
+	}
+
+	target := []rune(word)
+
+	firstRow := make([]int, len(target)+1)
+	for i := range firstRow {
+		firstRow[i] = i
+	}
+
+	var results []string
+	if node.isWord() && firstRow[len(target)] <= maxDist {
+		results = append(results, "")
+	}
+
+	var walk func(node trieCursor, prefix []rune, prevRow []int)
+	walk = func(node trieCursor, prefix []rune, prevRow []int) {
+		node.children(func(char rune, child trieCursor) {
+			row := make([]int, len(target)+1)
+			row[0] = prevRow[0] + 1
+			rowMin := row[0]
+
+			for i := 1; i <= len(target); i++ {
+				cost := 1
+				if target[i-1] == char {
+					cost = 0
+				}
+				row[i] = min(row[i-1]+1, prevRow[i]+1, prevRow[i-1]+cost)
+				rowMin = min(rowMin, row[i])
+			}
+
+			childPrefix := append(prefix[:len(prefix):len(prefix)], char)
+			if child.isWord() && row[len(target)] <= maxDist {
+				results = append(results, string(childPrefix))
+			}
+			if rowMin <= maxDist {
+				walk(child, childPrefix, row)
+			}
+		})
+	}
+	walk(node, nil, firstRow)
+
+	sort.Strings(results)
+	return results
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrieNode_FuzzySearch(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "cart", "cast", "at", "dog", "cot", "coat"} {
+		trie.Insert(word)
+	}
+
+	tests := []struct {
+		name     string
+		word     string
+		maxDist  int
+		expected []string
+	}{
+		{"distance 0 is exact", "cat", 0, []string{"cat"}},
+		{"distance 0 miss", "cab", 0, nil},
+		{"distance 1", "cat", 1, []string{"at", "cart", "cast", "cat", "coat", "cot"}},
+		{"distance 1 excludes distance 2", "cab", 1, []string{"cat"}},
+		{"distance 2", "cab", 2, []string{"at", "cart", "cast", "cat", "coat", "cot"}},
+		{"no neighbors within bound", "zebra", 1, nil},
+		{"negative distance", "cat", -1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := trie.FuzzySearch(tt.word, tt.maxDist)
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("FuzzySearch(%q, %d) = %v, expected %v", tt.word, tt.maxDist, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTrieNode_FuzzySearch_Unicode(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("café")
+
+	// é is one rune, so swapping it for e is a single substitution
+	got := trie.FuzzySearch("cafe", 1)
+	if len(got) != 1 || got[0] != "café" {
+		t.Errorf("FuzzySearch(\"cafe\", 1) = %v, expected [café]", got)
+	}
+}
+
+func BenchmarkFuzzySearch(b *testing.B) {
+	trie := NewTrieNode()
+	for _, word := range []string{"quartile", "quarter", "quart", "artist", "tile", "tiles", "startle"} {
+		trie.Insert(word)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trie.FuzzySearch("quartily", 1)
+	}
+}
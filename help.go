@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// printHelp displays usage information.
+func printHelp() {
+	fmt.Println("Apple Quartile Solver")
+	fmt.Println("Solves Apple News Quartile puzzles using WordNet dictionary.")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Printf("  %s [solve] [OPTIONS]\n", os.Args[0])
+	fmt.Printf("  %s build-cache --output PATH [DICTIONARY OPTIONS]\n", os.Args[0])
+	fmt.Printf("  %s export --output PATH [DICTIONARY OPTIONS]\n", os.Args[0])
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  solve                Solve puzzles (the default when no command is given)")
+	fmt.Println("  build-cache          Load the dictionary and save it as a trie file that")
+	fmt.Println("                       --dictionary loads without parsing WordNet again")
+	fmt.Println("  export               Load the dictionary and write it as a sorted wordlist")
+	fmt.Println()
+	fmt.Println("build-cache and export accept the options from --dictionary through --safe-list")
+	fmt.Println("below, plus --debug, --log-level, and --config.")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --dictionary PATH    Path to WordNet (wn_s.pl) or plain wordlist file; without it")
+	fmt.Println("                       a small built-in word list is used")
+	fmt.Println("  --dictionary-format FORMAT")
+	fmt.Println("                       Dictionary format: auto (default), wordnet, plain, scowl,")
+	fmt.Println("                       or trie")
+	fmt.Println("  --include-satellites=false")
+	fmt.Println("                       Skip WordNet adjective satellites, which mostly repeat adjectives")
+	fmt.Println("  --include-proper     Keep proper nouns such as place names (lowercased)")
+	fmt.Println("  --split-phrases      Load each word of multi-word WordNet entries separately")
+	fmt.Println("  --agent-nouns        Also generate -er agent nouns for verbs (run -> runner)")
+	fmt.Println("  --no-generate-forms  Load only the word forms WordNet lists, with no generated")
+	fmt.Println("                       plurals, verb forms, or comparatives")
+	fmt.Println("  --strict-prolog      Parse WordNet lines with a Prolog tokenizer that allows spaces,")
+	fmt.Println("                       comments, and backslash escapes and rejects stray matches")
+	fmt.Println("  --allowlist PATH     Add the words listed in PATH (one per line) after loading")
+	fmt.Println("  --blocklist PATH     Remove the words listed in PATH (one per line) after loading")
+	fmt.Println("  --variants PATH      Add spelling variants from PATH, one pair per line such as")
+	fmt.Println("                       \"color colour\", for each first word in the dictionary")
+	fmt.Println("  --safe               Remove offensive words using the built-in list")
+	fmt.Println("  --safe-list PATH     Use the words in PATH as the --safe list instead")
+	fmt.Println("  --validate-forms PATH")
+	fmt.Println("                       Report generated WordNet forms missing from the reference")
+	fmt.Println("                       wordlist at PATH, then exit")
+	fmt.Println("  --export-dict PATH   Write the loaded dictionary to PATH as a sorted wordlist;")
+	fmt.Println("                       --puzzle is optional")
+	fmt.Println("  --check WORD         Report whether WORD is in the dictionary and quartile-legal,")
+	fmt.Println("                       then exit (status 2 if it is not in the dictionary)")
+	fmt.Println("  --puzzle PATH        Path to puzzle file with letter combinations; repeat it or")
+	fmt.Println("                       pass a directory or glob to solve several puzzles")
+	fmt.Println("  --debug              Enable debug mode for verbose output (--log-level debug)")
+	fmt.Println("  --log-level LEVEL    Diagnostics on stderr: debug, info, or warn (default)")
+	fmt.Println("  --min-tile-length N  Warn about tiles shorter than N letters (default 2)")
+	fmt.Println("  --max-tile-length N  Warn about tiles longer than N letters (default 4)")
+	fmt.Println("  --strict-tiles       Fail instead of warning about out-of-range tile lengths")
+	fmt.Println("  --lenient            Strip non-letter characters from tiles instead of failing")
+	fmt.Println("  --interactive        Solve puzzles typed on stdin without reloading the dictionary")
+	fmt.Println("  --max-tiles N        Most tiles a single word may use (default 4)")
+	fmt.Println("  --max-candidates N   Refuse puzzles projecting more than N arrangements")
+	fmt.Println("                       (default 10000000, 0 for no limit)")
+	fmt.Println("  --dry-run            Validate inputs and report the projected search size without solving")
+	fmt.Println("  --scores SPEC        Points per tile count, e.g. 3=5,4=10 (default 1=1,2=2,3=4,4=8)")
+	fmt.Println("  --format FORMAT      Output format: text (default), json, quiet, or csv")
+	fmt.Println("  --show-tiles         Show each word split into its tiles, e.g. ca|st|le")
+	fmt.Println("  --quiet              Print only the found words, one per line (--format quiet)")
+	fmt.Println("  --order ORDER        Result order: tiles (default), rarity, or frequency")
+	fmt.Println("  --frequency PATH     Word frequency list (\"word count\" per line, or words most")
+	fmt.Println("                       common first) for --order frequency")
+	fmt.Println("  --limit N            Print only the first N results (default 0, no limit)")
+	fmt.Println("  --tile-frequency-weighted")
+	fmt.Println("                       With --limit, explore tiles that begin the most words")
+	fmt.Println("                       first and stop once N words are found")
+	fmt.Println("  --tiles N            Only show words formed from exactly N tiles (1 to --max-tiles)")
+	fmt.Println("  --contains SUBSTR    Only show words containing SUBSTR, e.g. str")
+	fmt.Println("  --anagram            List words spelled from the tiles' letters in any order,")
+	fmt.Println("                       ignoring tile boundaries")
+	fmt.Println("  --hint               Reveal one tile of one quartile instead of listing words")
+	fmt.Println("  --decompose WORD     Show the tile sequences that build WORD instead of listing")
+	fmt.Println("                       words (status 2 if none does)")
+	fmt.Println("  --seed N             Seed for randomized features such as --hint; the same seed")
+	fmt.Println("                       gives the same output (default 0, no randomness)")
+	fmt.Println("  --solution           Find quartiles that together use every tile exactly once")
+	fmt.Println("  --max-solutions N    Most --solution partitions to report (default 1, 0 for all)")
+	fmt.Println("  --allow-tile-reuse   Let --solution use a tile in more than one word")
+	fmt.Println("  --coverage           List tiles that no found word uses (likely typos)")
+	fmt.Println("  --tile-stats         Count how many found words use each tile, most used first")
+	fmt.Println("  --max-score          Report the best total score from found words that share no")
+	fmt.Println("                       tile, and the words that reach it")
+	fmt.Println("  --suggest            If no quartile is found, show near misses one edit away")
+	fmt.Println("  --timeout DURATION   Stop solving after DURATION (e.g. 2s) and show partial results")
+	fmt.Println("  --stats              Print candidate, prune, and match counts with timings")
+	fmt.Println("  --stem               Also match words that inflect a dictionary word by a common")
+	fmt.Println("                       suffix (jumped from jump), noting the base word")
+	fmt.Println("  --allow-partial-last-tile")
+	fmt.Println("                       Also list near misses whose last tile is one edit from")
+	fmt.Println("                       completing a word, noting the word")
+	fmt.Println("  --prefix-cache       Memoize trie prefix lookups while solving; logs the hit rate")
+	fmt.Println("                       at --log-level debug")
+	fmt.Println("  --history FILE       Append a record of each solve to a JSON history file")
+	fmt.Println("  --show-history       Print the records in --history FILE and exit")
+	fmt.Println("  --no-color           Disable colored output (automatic when stdout is not a")
+	fmt.Println("                       terminal or NO_COLOR is set)")
+	fmt.Println("  --color              Force colored output, overriding NO_COLOR")
+	fmt.Println("  --config PATH        JSON file of default flag values; explicit flags win")
+	fmt.Println("  --help               Show this help message")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Printf("  %s --dictionary ./prolog/wn_s.pl --puzzle ./samples/puzzle1.txt\n", os.Args[0])
+	fmt.Printf("  %s --debug --dictionary ./prolog/wn_s.pl --puzzle ./samples/puzzle2.txt\n", os.Args[0])
+	fmt.Println()
+	fmt.Println("Setup:")
+	fmt.Println("  curl -O https://wordnetcode.princeton.edu/3.0/WNprolog-3.0.tar.gz")
+	fmt.Println("  tar -xzf WNprolog-3.0.tar.gz")
+}
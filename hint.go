@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// pickHint returns one tile of one quartile among matches, or false if no
+// match uses quartileMaxTiles tiles. With a nil rng it takes the first tile
+// of the first quartile in result order; otherwise rng picks both the
+// quartile and the tile. Either way the same board and seed always give the
+// same hint.
+func pickHint(matches []Result, rng *rand.Rand) (string, bool) {
+	var quartiles []Result
+	for _, m := range matches {
+		if len(m.Tiles) == quartileMaxTiles {
+			quartiles = append(quartiles, m)
+		}
+	}
+	if len(quartiles) == 0 {
+		return "", false
+	}
+	if rng == nil {
+		return quartiles[0].Tiles[0], true
+	}
+	tiles := quartiles[rng.Intn(len(quartiles))].Tiles
+	return tiles[rng.Intn(len(tiles))], true
+}
+
+// printHint reveals a single quartile tile without naming the word.
+func printHint(w io.Writer, tile string, ok bool) {
+	if !ok {
+		fmt.Fprintln(w, "Hint: no quartile found to hint at")
+		return
+	}
+	fmt.Fprintf(w, "Hint: one quartile uses the tile %q\n", tile)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPickHint_IsTileOfQuartile(t *testing.T) {
+	trie := partitionTrie()
+	trie.Insert("cater") // a two-tile word must not be hinted at
+
+	matches, _, _ := findMatches(context.Background(), trie, partitionBoard, quartileMaxTiles, false, nil)
+	tile, ok := pickHint(matches, nil)
+	if !ok {
+		t.Fatal("Expected a hint for a board with quartiles")
+	}
+
+	var inQuartile bool
+	for _, q := range findQuartiles(trie, partitionBoard) {
+		if partitionBoard[q.tiles[0]] == tile {
+			inQuartile = true
+		}
+	}
+	if !inQuartile {
+		t.Errorf("Expected the hint %q to start a four-tile word", tile)
+	}
+
+	again, _ := pickHint(matches, nil)
+	if again != tile {
+		t.Errorf("Expected the same hint on every call, got %q then %q", tile, again)
+	}
+}
+
+func TestPickHint_NoQuartile(t *testing.T) {
+	if _, ok := pickHint([]Result{{Word: "cat", Tiles: []string{"c", "at"}}}, nil); ok {
+		t.Error("Expected no hint without a four-tile word")
+	}
+}
+
+func TestRun_HintHidesWords(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "caterpillar\ncat\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nter\npil\nlar\n")
+
+	var buf bytes.Buffer
+	if err := runWithOptions(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		hint:           true,
+	}, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, `Hint: one quartile uses the tile "ca"`) {
+		t.Errorf("Expected a hint naming the tile ca, got:\n%s", output)
+	}
+	if strings.Contains(output, "caterpillar") {
+		t.Errorf("Expected the hint not to reveal the word, got:\n%s", output)
+	}
+}
+
+// seededHint solves the puzzle with --hint and the given seed and
+// returns the output.
+func seededHint(t *testing.T, dictPath, puzzlePath string, seed int64) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := runWithOptions(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		hint:           true,
+		seed:           seed,
+	}, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestRun_HintSeed(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "caterpillar\nbutterfly\nhamburger\npentagons\nsophistry\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", strings.Join(partitionBoard, "\n"))
+
+	first := seededHint(t, dictPath, puzzlePath, 7)
+	if again := seededHint(t, dictPath, puzzlePath, 7); again != first {
+		t.Errorf("Expected identical output for the same seed, got:\n%s\nthen:\n%s", first, again)
+	}
+	if other := seededHint(t, dictPath, puzzlePath, 8); other == first {
+		t.Errorf("Expected a different hint for a different seed, got the same:\n%s", other)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// historyRecord is one solved puzzle saved to the history file.
+type historyRecord struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Tiles      []string  `json:"tiles"`
+	Matches    int       `json:"matches"`
+	TotalScore int       `json:"totalScore"`
+}
+
+// loadHistory reads all records from a JSON history file.
+// A missing file is treated as an empty history.
+func loadHistory(historyPath string) ([]historyRecord, error) {
+	data, err := os.ReadFile(historyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading history file %s: %w", historyPath, err)
+	}
+
+	var records []historyRecord
+	if len(data) == 0 {
+		return records, nil
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parsing history file %s: %w", historyPath, err)
+	}
+	return records, nil
+}
+
+// appendHistory adds a record to the end of the JSON history file,
+// creating the file if it does not exist.
+func appendHistory(historyPath string, record historyRecord) error {
+	records, err := loadHistory(historyPath)
+	if err != nil {
+		return err
+	}
+	records = append(records, record)
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding history: %w", err)
+	}
+	if err := os.WriteFile(historyPath, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing history file %s: %w", historyPath, err)
+	}
+	return nil
+}
+
+// showHistoryFile prints every record in the history file.
+func showHistoryFile(historyPath string, w io.Writer) error {
+	if historyPath == "" {
+		return errors.New("--show-history requires --history FILE")
+	}
+
+	records, err := loadHistory(historyPath)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		fmt.Fprintln(w, "No puzzles in history")
+		return nil
+	}
+
+	for i, record := range records {
+		fmt.Fprintf(w, "%2d. %s  %d words, %d points  [%s]\n",
+			i+1, record.Timestamp.Format(time.RFC3339), record.Matches,
+			record.TotalScore, strings.Join(record.Tiles, " "))
+	}
+	return nil
+}
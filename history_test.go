@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunWithOptions_History(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).\ns(100000002,1,'at',n,1,2).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\n")
+	historyPath := filepath.Join(t.TempDir(), "history.json")
+
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, historyPath: historyPath}
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+			t.Fatalf("runWithOptions() unexpected error: %v", err)
+		}
+	}
+
+	records, err := loadHistory(historyPath)
+	if err != nil {
+		t.Fatalf("loadHistory failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 history records, got %d", len(records))
+	}
+
+	// "at" (1 tile) and "cat" (2 tiles) score 1 + 2
+	record := records[0]
+	if strings.Join(record.Tiles, ",") != "c,at" {
+		t.Errorf("Expected tiles [c at], got %v", record.Tiles)
+	}
+	if record.Matches != 2 {
+		t.Errorf("Expected 2 matches, got %d", record.Matches)
+	}
+	if record.TotalScore != 3 {
+		t.Errorf("Expected total score 3, got %d", record.TotalScore)
+	}
+	if record.Timestamp.IsZero() {
+		t.Error("Expected timestamp to be set")
+	}
+
+	var buf bytes.Buffer
+	if err := showHistoryFile(historyPath, &buf); err != nil {
+		t.Fatalf("showHistoryFile failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "2 words, 3 points  [c at]") {
+		t.Errorf("Expected history listing to describe the solve, got %q", buf.String())
+	}
+}
+
+func TestShowHistoryFile_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := showHistoryFile(filepath.Join(t.TempDir(), "none.json"), &buf); err != nil {
+		t.Fatalf("showHistoryFile failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No puzzles in history") {
+		t.Errorf("Expected empty-history message, got %q", buf.String())
+	}
+
+	if err := showHistoryFile("", &buf); err == nil {
+		t.Error("Expected error when no history file is given")
+	}
+}
+
+func TestLoadHistory_Corrupt(t *testing.T) {
+	path := writeTempFile(t, "history.json", "{not json")
+	if _, err := loadHistory(path); err == nil {
+		t.Error("Expected error for corrupt history file")
+	}
+}
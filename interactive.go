@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// runInteractive repeatedly reads a puzzle from r and solves it with
+// solveBoard against the already-loaded trie, so the dictionary is only
+// loaded once per session and every option applies as it does to a puzzle
+// file. Each puzzle is one tile per line, ended by a blank line or EOF. An
+// error solving one puzzle is reported and the session goes on. Prompts,
+// warnings, and errors go to the notices writer, so with --format json, csv,
+// or quiet w receives only results. The session
+// ends at EOF or when "quit" or "exit" is entered. Cancelling ctx, as Ctrl-C
+// does, ends it too, even while waiting for input, and returns the context's
+// error.
+func runInteractive(ctx context.Context, trie wordTrie, load loadSummary, opts options, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	input := readLines(ctx, r)
+	puzzleNumber := 0
+	lineNumber := 0
+
+	notices := opts.notices()
+	for {
+		fmt.Fprintln(notices, "Enter tiles, one per line (blank line to solve, 'quit' to exit):")
+
+		tiles, quit := readInteractivePuzzle(ctx, input, &lineNumber, opts.lenient, notices)
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("interactive session stopped: %w", err)
+		}
+		minLength, maxLength := opts.tileLengthRange()
+		if err := checkTileLengths(tiles, minLength, maxLength, opts.strictTiles, notices); err != nil {
+			fmt.Fprintf(notices, "Error: %v\n", err)
+			tiles = nil
+		}
+
+		if len(tiles) > 0 {
+			puzzleNumber++
+			if opts.textOutput() {
+				fmt.Fprintf(w, "Puzzle %d: %s\n", puzzleNumber, strings.Join(tiles, " "))
+			}
+			found, err := solveBoard(ctx, trie, tiles, load, opts, w)
+			switch {
+			case err != nil:
+				fmt.Fprintf(notices, "Error: %v\n", err)
+			case found == 0 && !opts.dryRun:
+				fmt.Fprintln(notices, "No words found")
+			}
+		}
+
+		if quit {
+			break
+		}
+	}
+
+	if err := input.err(); err != nil {
+		return fmt.Errorf("reading interactive input: %w", err)
+	}
+	return nil
+}
+
+// lineInput delivers the lines of a reader on a channel, so a reader can stop
+// waiting for the next line when its context is cancelled.
+type lineInput struct {
+	lines <-chan string
+	// errs holds the read error, if any, once lines is closed
+	errs <-chan error
+}
+
+// readLines starts reading r line by line in the background. The lines
+// channel is closed at EOF, on a read error, or once ctx is done.
+func readLines(ctx context.Context, r io.Reader) lineInput {
+	lines := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := newLineScanner(r)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errs <- err
+		}
+	}()
+	return lineInput{lines: lines, errs: errs}
+}
+
+// err returns the error that ended reading, if any, once lines is closed.
+func (in lineInput) err() error {
+	select {
+	case err := <-in.errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+// readInteractivePuzzle reads tiles until a blank line. It reports quit when
+// input is exhausted, ctx is done, or the user asks to stop. Invalid tiles
+// are reported to w and skipped so one typo doesn't end the session.
+func readInteractivePuzzle(ctx context.Context, input lineInput, lineNumber *int, lenient bool, w io.Writer) (tiles []string, quit bool) {
+	for {
+		var text string
+		var ok bool
+		select {
+		case text, ok = <-input.lines:
+		case <-ctx.Done():
+			return nil, true
+		}
+		if !ok {
+			return tiles, true
+		}
+		*lineNumber++
+		line := strings.TrimSpace(text)
+		switch strings.ToLower(line) {
+		case "":
+			if len(tiles) > 0 {
+				return tiles, false
+			}
+		case "quit", "exit":
+			return tiles, true
+		default:
+			tile, err := parseTile(line, *lineNumber, lenient, w)
+			if err != nil {
+				fmt.Fprintf(w, "Error: %v\n", err)
+				continue
+			}
+			if tile != "" {
+				tiles = append(tiles, tile)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunInteractive(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "at", "dog", "do"} {
+		trie.Insert(word)
+	}
+
+	input := strings.NewReader("c\nat\n\n\ndo\ng\n")
+
+	var buf bytes.Buffer
+	if err := runInteractive(context.Background(), trie, loadSummary{}, options{}, input, &buf); err != nil {
+		t.Fatalf("runInteractive() unexpected error: %v", err)
+	}
+	output := buf.String()
+
+	for _, expected := range []string{"Puzzle 1: c at", "cat", "Puzzle 2: do g", "dog"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "Puzzle 3") {
+		t.Error("Expected blank lines between puzzles to not start an empty puzzle")
+	}
+
+	// Results for the first puzzle must come before the second header
+	if strings.Index(output, "cat") > strings.Index(output, "Puzzle 2") {
+		t.Error("Expected first puzzle's results before the second puzzle")
+	}
+}
+
+func TestRunInteractive_Quit(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+
+	input := strings.NewReader("ca\nt\nquit\nxx\n\n")
+
+	var buf bytes.Buffer
+	if err := runInteractive(context.Background(), trie, loadSummary{}, options{}, input, &buf); err != nil {
+		t.Fatalf("runInteractive() unexpected error: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.Contains(output, "cat") {
+		t.Error("Expected tiles entered before 'quit' to be solved")
+	}
+	if strings.Contains(output, "Puzzle 2") {
+		t.Error("Expected input after 'quit' to be ignored")
+	}
+}
+
+func TestRunInteractive_CancelWhileWaiting(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+
+	// The pipe delivers one puzzle and then blocks, like a terminal waiting
+	// for the user to type
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var buf safeBuffer
+	done := make(chan error, 1)
+	go func() {
+		done <- runInteractive(ctx, trie, loadSummary{}, options{}, reader, &buf)
+	}()
+
+	if _, err := io.WriteString(writer, "c\nat\n\n"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "Puzzle 1") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected runInteractive to return once cancelled while waiting for input")
+	}
+	if output := buf.String(); !strings.Contains(output, "cat") || strings.Contains(output, "stopped early") {
+		t.Errorf("Expected the first puzzle solved in full, got:\n%s", output)
+	}
+}
+
+// safeBuffer is a bytes.Buffer that may be written and read from different
+// goroutines.
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRunInteractive_PuzzleOptions(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "at"} {
+		trie.Insert(word)
+	}
+
+	tests := []struct {
+		name string
+		opts options
+		want string
+	}{
+		{"solution", options{solution: true}, "Solution"},
+		{"tile stats", options{tileStats: true}, "Tile usage:"},
+		{"max score", options{maxScore: true}, "Max score:"},
+		{"hint", options{hint: true}, "Hint:"},
+		{"decompose", options{decompose: "cat"}, `"cat" is built by:`},
+		{"dry run", options{dryRun: true}, "Dry run (no words solved):"},
+		{"anagram", options{anagram: true}, "cat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withColor(t, false)
+			var buf bytes.Buffer
+			if err := runInteractive(context.Background(), trie, loadSummary{words: 2}, tt.opts, strings.NewReader("c\nat\n"), &buf); err != nil {
+				t.Fatalf("runInteractive() unexpected error: %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("Expected %q in interactive output, got:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestRunInteractive_JSONKeepsStdoutClean(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+
+	var buf, notices bytes.Buffer
+	opts := options{format: outputJSON, stats: true, coverage: true, diagnostics: &notices}
+	if err := runInteractive(context.Background(), trie, loadSummary{}, opts, strings.NewReader("c\nat\nx7\n"), &buf); err != nil {
+		t.Fatalf("runInteractive() unexpected error: %v", err)
+	}
+
+	var results []Result
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("Expected only JSON results on stdout, got %v:\n%s", err, buf.String())
+	}
+	for _, want := range []string{"Enter tiles", "Error: invalid tile", "Coverage", "Pruned"} {
+		if !strings.Contains(notices.String(), want) {
+			t.Errorf("Expected %q on the notices writer, got:\n%s", want, notices.String())
+		}
+	}
+}
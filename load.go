@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// loadSolverDictionary returns the dictionary to solve against and its word
+// count. A --dictionary trie file is queried in place through its mapping,
+// which the caller closes, unless the run edits the words or writes a trie
+// cache, which need a TrieNode. Any other dictionary is loaded into a
+// TrieNode, and the allowlist, spelling variants, blocklist, and --safe
+// list are applied to it. Loading stops with the context's error once ctx
+// is cancelled.
+func loadSolverDictionary(ctx context.Context, opts options) (wordTrie, int, error) {
+	if opts.dictionaryPath != "" && !opts.editsDictionary() && opts.cachePath == "" {
+		format, err := resolveDictionaryFormat(opts.dictionaryPath, opts.dictionaryFormat)
+		if err != nil {
+			return nil, 0, fmt.Errorf("loading dictionary from %s: %w", opts.dictionaryPath, err)
+		}
+		if format == formatTrie {
+			return openTrieDictionary(ctx, opts.dictionaryPath)
+		}
+	}
+
+	trie := NewTrieNode()
+	var skips loadSkips
+	wordCount, err := loadConfiguredDictionary(ctx, opts, trie, &skips)
+	if err != nil {
+		return nil, 0, fmt.Errorf("loading dictionary from %s: %w", dictionaryName(opts), err)
+	}
+	if skips.any() {
+		logger.Info("skipped dictionary entries", "proper_nouns", skips.ProperNouns, "satellites", skips.Satellites,
+			"possessives", skips.Possessives, "malformed", skips.Malformed, "phrases", skips.Phrases)
+		if opts.textOutput() {
+			fmt.Fprintf(opts.notices(), "Dictionary: %s\n", skips)
+		}
+	}
+
+	if opts.allowlistPath != "" {
+		added, err := applyAllowlist(trie, opts.allowlistPath)
+		if err != nil {
+			return nil, 0, fmt.Errorf("applying allowlist %s: %w", opts.allowlistPath, err)
+		}
+		wordCount += added
+	}
+
+	if opts.variantsPath != "" {
+		added, err := applyVariants(trie, opts.variantsPath)
+		if err != nil {
+			return nil, 0, fmt.Errorf("applying spelling variants %s: %w", opts.variantsPath, err)
+		}
+		wordCount += added
+		logger.Info("applied spelling variants", "path", opts.variantsPath, "added", added)
+	}
+
+	if opts.blocklistPath != "" {
+		removed, err := applyBlocklist(trie, opts.blocklistPath)
+		if err != nil {
+			return nil, 0, fmt.Errorf("applying blocklist %s: %w", opts.blocklistPath, err)
+		}
+		wordCount -= removed
+		logger.Info("applied blocklist", "path", opts.blocklistPath, "removed", removed)
+	}
+
+	if opts.safe || opts.safeListPath != "" {
+		removed, err := applySafeFilter(trie, opts.safeListPath)
+		if err != nil {
+			return nil, 0, fmt.Errorf("applying safe word list: %w", err)
+		}
+		wordCount -= removed
+		logger.Info("applied safe word list", "removed", removed)
+	}
+
+	return trie, wordCount, nil
+}
+
+// openTrieDictionary opens the trie file at path for solving against in
+// place and returns it with its word count.
+func openTrieDictionary(ctx context.Context, path string) (wordTrie, int, error) {
+	logger.Info("detected dictionary format", "path", path, "format", formatTrie)
+	mapped, err := OpenTrieFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("loading dictionary from %s: %w", path, err)
+	}
+	if err := ctx.Err(); err != nil {
+		mapped.Close()
+		return nil, 0, fmt.Errorf("loading dictionary from %s: %w", path, err)
+	}
+	return mapped, mapped.CountPrefix(""), nil
+}
+
+// dictionaryName names the --dictionary file in messages, or the embedded
+// default wordlist when none is given.
+func dictionaryName(opts options) string {
+	if opts.dictionaryPath == "" {
+		return defaultDictionaryName
+	}
+	return opts.dictionaryPath
+}
+
+// loadConfiguredDictionary loads the --dictionary file into the trie, or
+// the embedded default wordlist when no path is given, counting the entries
+// it leaves out in skips.
+func loadConfiguredDictionary(ctx context.Context, opts options, trie *TrieNode, skips *loadSkips) (int, error) {
+	load := opts.loadOptions()
+	load.skips = skips
+	if opts.dictionaryPath == "" {
+		return loadDefaultDictionary(ctx, trie, load)
+	}
+	return loadDictionaryFile(ctx, opts.dictionaryPath, opts.dictionaryFormat, trie, load)
+}
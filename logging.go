@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Log levels accepted by --log-level.
+const (
+	logLevelDebug = "debug"
+	logLevelInfo  = "info"
+	logLevelWarn  = "warn"
+)
+
+// logger receives diagnostic records such as per-line parse details. main
+// sets it once from --log-level and --debug before any work starts; until
+// then only warnings reach stderr.
+var logger = newLogger(os.Stderr, slog.LevelWarn)
+
+// newLogger returns a logger writing text records at or above level to w.
+func newLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// parseLogLevel converts a --log-level value to a slog level. An empty value
+// means warn.
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case logLevelDebug:
+		return slog.LevelDebug, nil
+	case logLevelInfo:
+		return slog.LevelInfo, nil
+	case "", logLevelWarn:
+		return slog.LevelWarn, nil
+	}
+	return 0, fmt.Errorf("--log-level must be %q, %q, or %q, got %q", logLevelDebug, logLevelInfo, logLevelWarn, level)
+}
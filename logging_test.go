@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// withLogger points logger at a buffer logging at level until the test ends.
+func withLogger(t *testing.T, level slog.Level) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := logger
+	logger = newLogger(&buf, level)
+	t.Cleanup(func() { logger = previous })
+	return &buf
+}
+
+func TestLogger_Levels(t *testing.T) {
+	path := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).\ninvalid line\n")
+
+	buf := withLogger(t, slog.LevelDebug)
+	if _, err := loadDictionary(context.Background(), path, NewTrieNode(), true); err != nil {
+		t.Fatalf("loadDictionary failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "level=DEBUG") || !strings.Contains(buf.String(), `msg="skipping unparsable line" line=2`) {
+		t.Errorf("Expected debug records at debug level, got %q", buf.String())
+	}
+
+	buf = withLogger(t, slog.LevelInfo)
+	if _, err := loadDictionary(context.Background(), path, NewTrieNode(), true); err != nil {
+		t.Fatalf("loadDictionary failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "level=DEBUG") {
+		t.Errorf("Expected debug records suppressed at info level, got %q", buf.String())
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	for input, want := range map[string]slog.Level{
+		"":      slog.LevelWarn,
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+	} {
+		if got, err := parseLogLevel(input); err != nil || got != want {
+			t.Errorf("parseLogLevel(%q) = %v, %v, expected %v", input, got, err, want)
+		}
+	}
+	if _, err := parseLogLevel("trace"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
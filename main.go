@@ -4,350 +4,344 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"errors"
-	"flag"
 	"fmt"
 	"io"
 	"os"
-	"regexp"
-	"strings"
 	"time"
 )
 
 // Sentinel errors for common failure cases.
 var (
-	ErrEmptyPuzzle = errors.New("puzzle file is empty")
+	ErrEmptyPuzzle        = errors.New("puzzle file is empty")
+	ErrDictionaryNotFound = errors.New("dictionary file not found")
+	ErrDictionaryDenied   = errors.New("permission denied reading dictionary file")
+	ErrPuzzleNotFound     = errors.New("puzzle file not found")
+	ErrInvalidTile        = errors.New("invalid tile")
+	ErrTooManyCandidates  = errors.New("too many candidates")
+	ErrNotWordNet         = errors.New("dictionary is not in WordNet format")
+	ErrTileLength         = errors.New("tile length out of range")
 )
 
-// ANSI color codes for terminal output
-const (
-	Reset = "\033[0m"
-	Gray  = "\033[90m"
-	Green = "\033[32m"
-	Red   = "\033[31m"
-)
-
-// TrieNode represents a node in the trie data structure for efficient word lookup.
-type TrieNode struct {
-	Children map[rune]*TrieNode
-	IsEnd    bool
+// run executes the main application logic with the given parameters.
+// It returns an error if any step fails, allowing for testable error handling.
+// Cancelling ctx stops loading or solving and returns the context's error.
+func run(ctx context.Context, dictionaryPath, puzzlePath string, debug bool, w io.Writer) error {
+	return runWithOptions(ctx, options{
+		dictionaryPath: dictionaryPath,
+		puzzlePaths:    []string{puzzlePath},
+		debug:          debug,
+	}, w)
 }
 
-// NewTrieNode creates and initializes a new trie node.
-func NewTrieNode() *TrieNode {
-	return &TrieNode{
-		Children: make(map[rune]*TrieNode),
-		IsEnd:    false,
-	}
+// runWithOptions executes the solver using the full set of options.
+func runWithOptions(ctx context.Context, opts options, w io.Writer) error {
+	_, err := runCountingMatches(ctx, opts, w)
+	return err
 }
 
-// Insert adds a word to the trie.
-func (t *TrieNode) Insert(word string) {
-	node := t
-	for _, char := range word {
-		if _, exists := node.Children[char]; !exists {
-			node.Children[char] = NewTrieNode()
-		}
-		node = node.Children[char]
-	}
-	node.IsEnd = true
-}
+// runCountingMatches executes the solver like runWithOptions and also
+// returns the number of words found across all puzzles. Interactive, dry,
+// and export-only runs solve nothing up front and always report zero; a
+// --check run reports 1 if the word is in the dictionary and 0 if not.
+func runCountingMatches(ctx context.Context, opts options, w io.Writer) (int, error) {
+	dictionaryPath, debug := opts.dictionaryPath, opts.debug
 
-// Search returns true if the word exists in the trie.
-func (t *TrieNode) Search(word string) bool {
-	node := t
-	for _, char := range word {
-		if _, exists := node.Children[char]; !exists {
-			return false
-		}
-		node = node.Children[char]
+	if opts.maxTiles < 0 {
+		return 0, fmt.Errorf("--max-tiles must be at least 1, got %d", opts.maxTiles)
 	}
-	return node.IsEnd
-}
-
-// generatePlural generates the plural form of a noun using basic English rules.
-func generatePlural(word string) string {
-	if strings.HasSuffix(word, "s") || strings.HasSuffix(word, "sh") ||
-		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "x") ||
-		strings.HasSuffix(word, "z") {
-		return word + "es"
+	if opts.exactTiles < 0 || opts.exactTiles > opts.tileLimit() {
+		return 0, fmt.Errorf("--tiles must be between 1 and %d, got %d", opts.tileLimit(), opts.exactTiles)
 	}
-	if strings.HasSuffix(word, "y") && len(word) > 1 &&
-		!strings.Contains("aeiou", string(word[len(word)-2])) {
-		return word[:len(word)-1] + "ies"
+	if opts.limit < 0 {
+		return 0, fmt.Errorf("--limit must be 0 or more, got %d", opts.limit)
 	}
-	return word + "s"
-}
-
-// generateVerbForms generates past tense and present participle forms of a verb.
-func generateVerbForms(word string) (past, participle string) {
-	// Past tense
-	if strings.HasSuffix(word, "e") {
-		past = word + "d"
-	} else {
-		past = word + "ed"
+	if minLength, maxLength := opts.tileLengthRange(); minLength < 1 || maxLength < minLength {
+		return 0, fmt.Errorf("--min-tile-length and --max-tile-length must satisfy 1 <= min <= max, got %d and %d", minLength, maxLength)
 	}
-
-	// Present participle
-	if strings.HasSuffix(word, "e") && len(word) > 1 {
-		participle = word[:len(word)-1] + "ing"
-	} else {
-		participle = word + "ing"
+	if opts.maxSolutions < 0 {
+		return 0, fmt.Errorf("--max-solutions must be 0 or more, got %d", opts.maxSolutions)
 	}
-
-	return past, participle
-}
-
-// loadDictionary loads words from a WordNet Prolog file into the trie.
-// It parses the WordNet synset format and generates common word forms
-// (plurals for nouns, past tense and participles for verbs).
-//
-// Parameters:
-//   - dictionaryPath: path to the WordNet Prolog dictionary file (wn_s.pl)
-//   - trie: the trie data structure to populate with words
-//   - debug: if true, prints verbose parsing information
-//
-// Returns the number of words loaded and any error encountered.
-func loadDictionary(dictionaryPath string, trie *TrieNode, debug bool) (int, error) {
-	dictionaryFile, err := os.Open(dictionaryPath)
-	if err != nil {
-		return 0, fmt.Errorf("opening dictionary file: %w", err)
+	if err := validateOrder(opts.order); err != nil {
+		return 0, err
+	}
+	if opts.order == orderFrequency && opts.frequencyPath == "" {
+		return 0, fmt.Errorf("--order %s requires --frequency", orderFrequency)
+	}
+	if _, err := newPrinter(opts.format); err != nil {
+		return 0, err
+	}
+	if opts.tileLimit() > maxTilesWarnThreshold {
+		fmt.Fprintf(opts.notices(), "Warning: --max-tiles %d grows the search factorially and may be very slow\n", opts.tileLimit())
 	}
-	defer dictionaryFile.Close()
-
-	scanner := bufio.NewScanner(dictionaryFile)
-	wordCount := 0
-
-	// WordNet format: s(synset_id,w_num,'word',pos,sense_num,tag_count).
-	re := regexp.MustCompile(`s\(\d+,\d+,'([^']+)',([nvasr]),\d+,\d+\)\.?`)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if debug {
-			fmt.Printf(Gray+"Reading line: %s"+Reset+"\n", line)
-		}
+	// Validate input files exist; no dictionary means the embedded one
+	if dictionaryPath == "" {
+		dictionaryPath = defaultDictionaryName
+	} else if _, err := os.Stat(dictionaryPath); os.IsNotExist(err) {
+		return 0, fmt.Errorf("%w: %s", ErrDictionaryNotFound, dictionaryPath)
+	} else if err != nil {
+		return 0, dictionaryOpenError(dictionaryPath, err)
+	}
 
-		matches := re.FindStringSubmatch(line)
-		if len(matches) != 3 {
-			if debug {
-				fmt.Printf(Gray+"Failed to parse line: %s"+Reset+"\n", line)
-			}
-			continue
+	var puzzlePaths []string
+	if !opts.interactive && !opts.exportOnly() && opts.checkWord == "" {
+		var err error
+		puzzlePaths, err = expandPuzzlePaths(opts.puzzlePaths)
+		if err != nil {
+			return 0, err
 		}
+	}
 
-		word := strings.TrimSpace(matches[1])
-		partOfSpeech := matches[2]
+	var heapBefore uint64
+	if debug {
+		heapBefore = heapInUse()
+	}
 
-		// Skip capitalized words (proper nouns)
-		if len(word) > 0 && word[0] >= 'A' && word[0] <= 'Z' {
-			continue
-		}
+	startTime := time.Now()
 
-		word = strings.ToLower(word)
+	if !debug && opts.textOutput() {
+		fmt.Fprintln(opts.notices(), "Loading dictionary from:", dictionaryPath)
+	}
 
-		// Insert the base word
-		trie.Insert(word)
-		wordCount++
+	trie, wordCount, err := loadSolverDictionary(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	if mapped, ok := trie.(*TrieFile); ok {
+		defer mapped.Close()
+	}
 
-		// Generate and insert plural forms for nouns
-		if partOfSpeech == "n" {
-			plural := generatePlural(word)
-			trie.Insert(plural)
-			wordCount++
+	if opts.frequencyPath != "" {
+		if opts.frequencies, err = readFrequencyList(opts.frequencyPath); err != nil {
+			return 0, err
 		}
+	}
 
-		// Generate and insert verb forms
-		if partOfSpeech == "v" {
-			past, participle := generateVerbForms(word)
-			trie.Insert(past)
-			trie.Insert(participle)
-			wordCount += 2
+	loadDuration := time.Since(startTime)
+	logger.Info("loaded dictionary", "path", dictionaryPath, "words", wordCount, "duration", loadDuration)
+	if debug {
+		fmt.Fprintf(opts.notices(), "Loaded %d words into trie in %v\n", wordCount, loadDuration)
+		var heapBytes uint64
+		if heapAfter := heapInUse(); heapAfter > heapBefore {
+			heapBytes = heapAfter - heapBefore
+		}
+		switch trie := trie.(type) {
+		case *TrieFile:
+			fmt.Fprintf(opts.notices(), "Trie file: %d bytes, queried in place\n", len(trie.data))
+		case *TrieNode:
+			printTrieReport(opts.notices(), trie, heapBytes)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return 0, fmt.Errorf("scanning dictionary file: %w", err)
+	if opts.exportDictPath != "" {
+		exported, err := exportDictionary(trie, opts.exportDictPath)
+		if err != nil {
+			return 0, err
+		}
+		fmt.Fprintf(opts.notices(), "Exported %d words to %s\n", exported, opts.exportDictPath)
 	}
 
-	return wordCount, nil
-}
-
-// generatePermutations generates all possible word combinations from puzzle tiles.
-// It creates combinations of 1 to maxLines tiles, then generates all permutations
-// of each combination.
-func generatePermutations(lines []string, maxLines int) []string {
-	var results []string
-
-	for i := 1; i <= maxLines; i++ {
-		combinations := combinations(lines, i)
-		for _, combo := range combinations {
-			perms := permutations(combo)
-			for _, perm := range perms {
-				results = append(results, strings.Join(perm, ""))
-			}
+	if opts.cachePath != "" {
+		// loadSolverDictionary builds a TrieNode whenever there is a cache path
+		if err := BuildTrieFile(trie.(*TrieNode), opts.cachePath); err != nil {
+			return 0, err
 		}
+		fmt.Fprintf(opts.notices(), "Cached %d words in %s\n", wordCount, opts.cachePath)
 	}
-	return results
-}
 
-// permutations generates all permutations of a slice of strings.
-func permutations(arr []string) [][]string {
-	var result [][]string
-
-	if len(arr) == 0 {
-		return result
+	if opts.checkWord != "" {
+		check := checkWord(trie, opts.checkWord)
+		printWordCheck(opts.reports(w), check)
+		if check.InDictionary {
+			return 1, nil
+		}
+		return 0, nil
 	}
 
-	if len(arr) == 1 {
-		return [][]string{arr}
+	if opts.exportOnly() {
+		return 0, nil
 	}
 
-	for i := 0; i < len(arr); i++ {
-		current := arr[i]
-		remaining := append(append([]string{}, arr[:i]...), arr[i+1:]...)
-		subPerms := permutations(remaining)
-		for _, subPerm := range subPerms {
-			result = append(result, append([]string{current}, subPerm...))
-		}
+	load := loadSummary{words: wordCount, duration: loadDuration}
+	if opts.interactive {
+		return 0, runInteractive(ctx, trie, load, opts, os.Stdin, w)
 	}
 
-	return result
+	return solvePuzzleFiles(ctx, trie, puzzlePaths, load, opts, w)
 }
 
-// combinations generates all combinations of r elements from arr.
-func combinations(arr []string, r int) [][]string {
-	var result [][]string
-	var f func([]string, int, []string)
-	f = func(arr []string, n int, temp []string) {
-		if len(temp) == r {
-			result = append(result, append([]string{}, temp...))
-			return
-		}
-		for i := n; i < len(arr); i++ {
-			f(arr, i+1, append(temp, arr[i]))
-		}
+// solvePuzzleFiles solves each puzzle file in turn and returns the total
+// number of words found. Text output heads each puzzle with its path when
+// there are several; JSON and CSV output instead print them all as one
+// document through a resultBatch, which is closed even if a puzzle fails.
+func solvePuzzleFiles(ctx context.Context, trie wordTrie, puzzlePaths []string, load loadSummary, opts options, w io.Writer) (totalFound int, err error) {
+	opts.batch = newResultBatch(opts.format, len(puzzlePaths))
+	if opts.batch != nil {
+		defer func() {
+			if finishErr := opts.batch.finish(w); err == nil && finishErr != nil {
+				err = fmt.Errorf("writing results: %w", finishErr)
+			}
+		}()
 	}
-	f(arr, 0, []string{})
-	return result
-}
 
-// checkInTrie validates permutations against the dictionary and prints valid words.
-func checkInTrie(trie *TrieNode, permutations []string, debug bool) {
-	count := 0
-	for _, perm := range permutations {
-		if trie.Search(perm) {
-			count++
-			fmt.Printf(Gray+"%2d. "+Green+"%s"+Reset+"\n", count, perm)
-		} else if debug {
-			fmt.Printf(Red+"Not found in trie: %s"+Reset+"\n", perm)
+	for i, puzzlePath := range puzzlePaths {
+		if err := ctx.Err(); err != nil {
+			return totalFound, err
 		}
+		if len(puzzlePaths) > 1 && opts.textOutput() {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "=== %s ===\n", puzzlePath)
+		}
+		if opts.batch != nil {
+			opts.batch.puzzle = puzzlePath
+		}
+		found, err := solvePuzzleFile(ctx, trie, puzzlePath, load, opts, w)
+		if err != nil {
+			return totalFound, err
+		}
+		totalFound += found
 	}
+	return totalFound, nil
 }
 
-// printHelp displays usage information.
-func printHelp() {
-	fmt.Println("Apple Quartile Solver")
-	fmt.Println("Solves Apple News Quartile puzzles using WordNet dictionary.")
-	fmt.Println()
-	fmt.Println("Usage:")
-	fmt.Printf("  %s [OPTIONS]\n", os.Args[0])
-	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  --dictionary PATH    Path to WordNet dictionary file (wn_s.pl)")
-	fmt.Println("  --puzzle PATH        Path to puzzle file with letter combinations")
-	fmt.Println("  --debug              Enable debug mode for verbose output")
-	fmt.Println("  --help               Show this help message")
-	fmt.Println()
-	fmt.Println("Examples:")
-	fmt.Printf("  %s --dictionary ./prolog/wn_s.pl --puzzle ./samples/puzzle1.txt\n", os.Args[0])
-	fmt.Printf("  %s --debug --dictionary ./prolog/wn_s.pl --puzzle ./samples/puzzle2.txt\n", os.Args[0])
-	fmt.Println()
-	fmt.Println("Setup:")
-	fmt.Println("  curl -O https://wordnetcode.princeton.edu/3.0/WNprolog-3.0.tar.gz")
-	fmt.Println("  tar -xzf WNprolog-3.0.tar.gz")
+// loadSummary describes the loaded dictionary for the reports that mention
+// it, such as --dry-run and --stats.
+type loadSummary struct {
+	words    int
+	duration time.Duration
 }
 
-// run executes the main application logic with the given parameters.
-// It returns an error if any step fails, allowing for testable error handling.
-func run(dictionaryPath, puzzlePath string, debug bool, w io.Writer) error {
-	// Validate input files exist
-	if _, err := os.Stat(dictionaryPath); os.IsNotExist(err) {
-		return fmt.Errorf("dictionary file not found: %s", dictionaryPath)
+// solvePuzzleFile reads one puzzle file and solves it with solveBoard,
+// returning the number of words found.
+func solvePuzzleFile(ctx context.Context, trie wordTrie, puzzlePath string, load loadSummary, opts options, w io.Writer) (int, error) {
+	tiles, err := readPuzzle(puzzlePath, opts.lenient, opts.notices())
+	if err != nil {
+		return 0, err
 	}
-
-	if _, err := os.Stat(puzzlePath); os.IsNotExist(err) {
-		return fmt.Errorf("puzzle file not found: %s", puzzlePath)
+	minLength, maxLength := opts.tileLengthRange()
+	if err := checkTileLengths(tiles, minLength, maxLength, opts.strictTiles, opts.notices()); err != nil {
+		return 0, fmt.Errorf("puzzle file %s: %w", puzzlePath, err)
 	}
+	return solveBoard(ctx, trie, tiles, load, opts, w)
+}
 
-	startTime := time.Now()
-
-	if !debug {
-		fmt.Fprintln(w, "Loading dictionary from:", dictionaryPath)
+// solveBoard solves one board of tiles, read from a puzzle file or typed
+// in --interactive mode, the same way for both: it reports the board's size
+// in a dry run, or prints the words, hint, decomposition, or anagrams asked
+// for, followed by any requested solutions, coverage, tile stats, best
+// score, suggestions, and stats, and records the solve in the history. It
+// returns the number of words found. The reports are text, so with another
+// --format they go to the notices writer and stdout keeps only results.
+func solveBoard(ctx context.Context, trie wordTrie, tiles []string, load loadSummary, opts options, w io.Writer) (int, error) {
+	reports := opts.reports(w)
+	if opts.dryRun {
+		printDryRun(reports, trie, tiles, load.words, opts)
+		return 0, nil
 	}
 
-	trie := NewTrieNode()
-	wordCount, err := loadDictionary(dictionaryPath, trie, debug)
-	if err != nil {
-		return fmt.Errorf("loading dictionary from %s: %w", dictionaryPath, err)
+	if opts.anagram {
+		results := anagramResults(trie, tiles)
+		if err := printResults(w, results, opts); err != nil {
+			return 0, fmt.Errorf("writing results: %w", err)
+		}
+		return len(results), nil
 	}
 
-	if debug {
-		loadDuration := time.Since(startTime)
-		fmt.Fprintf(w, "Loaded %d words into trie in %v\n", wordCount, loadDuration)
+	if err := checkCandidateLimit(len(tiles), opts.tileLimit(), opts.maxCandidates); err != nil {
+		return 0, err
 	}
 
-	// Read puzzle file
-	puzzleFile, err := os.Open(puzzlePath)
-	if err != nil {
-		return fmt.Errorf("opening puzzle file %s: %w", puzzlePath, err)
+	// Decomposing one word replaces the word list
+	if opts.decompose != "" {
+		sequences, err := decompose(ctx, tiles, opts.decompose, opts.tileLimit())
+		if err != nil {
+			return 0, err
+		}
+		printDecomposition(reports, opts.decompose, trie.Search(normalizeWord(opts.decompose)), sequences)
+		return len(sequences), nil
 	}
-	defer puzzleFile.Close()
 
-	var tiles []string
-	scanner := bufio.NewScanner(puzzleFile)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			tiles = append(tiles, line)
+	// A hint replaces the word list so the answers stay hidden
+	if opts.hint {
+		matches, _, _, err := solveTiles(ctx, trie, tiles, opts)
+		if err != nil {
+			fmt.Fprintf(opts.notices(), "Solve stopped early (%v); the hint may miss quartiles\n", err)
 		}
+		tile, ok := pickHint(matches, opts.rng())
+		printHint(reports, tile, ok)
+		return len(matches), nil
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("reading puzzle file %s: %w", puzzlePath, err)
+	matches, stats := solvePuzzle(ctx, trie, tiles, opts, w)
+	if opts.solution {
+		quartiles := findQuartiles(trie, tiles)
+		if opts.allowTileReuse {
+			printSolutions(reports, tiles, findCovers(tiles, quartiles, opts.maxSolutions), opts.scores)
+		} else {
+			printSolutions(reports, tiles, findSolutions(tiles, quartiles, opts.maxSolutions), opts.scores)
+		}
 	}
-
-	if len(tiles) == 0 {
-		return fmt.Errorf("puzzle file %s is empty", puzzlePath)
+	if opts.coverage {
+		printCoverage(reports, tiles, matches)
 	}
-
-	// Generate all permutations and validate against dictionary
-	perms := generatePermutations(tiles, 4)
-	checkInTrie(trie, perms, debug)
-
-	return nil
+	if opts.tileStats {
+		printTileStats(reports, tiles, matches)
+	}
+	if opts.maxScore {
+		printMaxScore(reports, tiles, matches)
+	}
+	if opts.suggest && !hasQuartile(matches) {
+		suggestions, err := suggestTiles(ctx, trie, tiles, opts)
+		switch {
+		case errors.Is(err, ErrTooManyCandidates):
+			fmt.Fprintf(opts.notices(), "Skipping near misses: %v\n", err)
+		case err != nil:
+			fmt.Fprintf(opts.notices(), "Near-miss search stopped early (%v); some may be missing\n", err)
+			fallthrough
+		default:
+			printSuggestions(reports, suggestions)
+		}
+	}
+	if opts.stats {
+		stats.LoadDuration = load.duration
+		printStats(reports, stats)
+	}
+	return len(matches), recordHistory(opts, tiles, matches)
 }
 
-func main() {
-	debug := flag.Bool("debug", false, "Enable debug mode")
-	dictionaryPath := flag.String("dictionary", "", "Path to the dictionary file")
-	puzzlePath := flag.String("puzzle", "", "Path to the puzzle text file")
-	help := flag.Bool("help", false, "Show usage information")
-	flag.Parse()
-
-	if *help {
-		printHelp()
-		return
+// recordHistory appends the solve to the history file when one is configured.
+func recordHistory(opts options, tiles []string, matches []Result) error {
+	if opts.historyPath == "" {
+		return nil
 	}
 
-	if *dictionaryPath == "" || *puzzlePath == "" {
-		fmt.Fprintf(os.Stderr, "Error: Both --dictionary and --puzzle are required\n")
-		fmt.Fprintf(os.Stderr, "Run with --help for usage information\n")
-		os.Exit(1)
+	record := historyRecord{
+		Timestamp:  time.Now(),
+		Tiles:      tiles,
+		Matches:    len(matches),
+		TotalScore: totalScore(matches),
 	}
+	return appendHistory(opts.historyPath, record)
+}
 
-	if err := run(*dictionaryPath, *puzzlePath, *debug, os.Stdout); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+// solvePuzzle finds every word formed from the tiles and prints them,
+// followed by any --stem and --allow-partial-last-tile matches. It returns
+// only the dictionary words, which are all the callers score and count.
+func solvePuzzle(ctx context.Context, trie wordTrie, tiles []string, opts options, w io.Writer) ([]Result, Stats) {
+	results, loose, stats, err := solveTiles(ctx, trie, tiles, opts)
+	shown := results
+	if len(loose) > 0 {
+		shown = append(append([]Result{}, results...), loose...)
+	}
+	if printErr := printResults(w, shown, opts); printErr != nil {
+		fmt.Fprintf(opts.notices(), "Error: writing results: %v\n", printErr)
+	}
+	if err != nil {
+		fmt.Fprintf(opts.notices(), "Solve stopped early (%v); results are partial\n", err)
 	}
+	return results, stats
 }
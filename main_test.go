@@ -3,9 +3,14 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -40,30 +45,30 @@ s(100000003,1,'run',v,1,3).`
 	puzzleFile.Close()
 
 	t.Run("successful run", func(t *testing.T) {
-		var buf bytes.Buffer
-		err := run(dictFile.Name(), puzzleFile.Name(), false, &buf)
-		if err != nil {
-			t.Errorf("run() unexpected error: %v", err)
+		var buf, diagnostics bytes.Buffer
+		opts := options{dictionaryPath: dictFile.Name(), puzzlePaths: []string{puzzleFile.Name()}, diagnostics: &diagnostics}
+		if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+			t.Errorf("runWithOptions() unexpected error: %v", err)
 		}
-		if !strings.Contains(buf.String(), "Loading dictionary") {
-			t.Error("Expected output to contain 'Loading dictionary'")
+		if !strings.Contains(diagnostics.String(), "Loading dictionary") {
+			t.Error("Expected diagnostics to contain 'Loading dictionary'")
 		}
 	})
 
 	t.Run("debug mode", func(t *testing.T) {
-		var buf bytes.Buffer
-		err := run(dictFile.Name(), puzzleFile.Name(), true, &buf)
-		if err != nil {
-			t.Errorf("run() unexpected error: %v", err)
+		var buf, diagnostics bytes.Buffer
+		opts := options{dictionaryPath: dictFile.Name(), puzzlePaths: []string{puzzleFile.Name()}, debug: true, diagnostics: &diagnostics}
+		if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+			t.Errorf("runWithOptions() unexpected error: %v", err)
 		}
-		if !strings.Contains(buf.String(), "Loaded") {
-			t.Error("Expected debug output to contain 'Loaded'")
+		if !strings.Contains(diagnostics.String(), "Loaded") {
+			t.Error("Expected debug diagnostics to contain 'Loaded'")
 		}
 	})
 
 	t.Run("dictionary not found", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := run("/nonexistent/dict.pl", puzzleFile.Name(), false, &buf)
+		err := run(context.Background(), "/nonexistent/dict.pl", puzzleFile.Name(), false, &buf)
 		if err == nil {
 			t.Error("Expected error for missing dictionary")
 		}
@@ -74,7 +79,7 @@ s(100000003,1,'run',v,1,3).`
 
 	t.Run("puzzle not found", func(t *testing.T) {
 		var buf bytes.Buffer
-		err := run(dictFile.Name(), "/nonexistent/puzzle.txt", false, &buf)
+		err := run(context.Background(), dictFile.Name(), "/nonexistent/puzzle.txt", false, &buf)
 		if err == nil {
 			t.Error("Expected error for missing puzzle")
 		}
@@ -92,7 +97,7 @@ s(100000003,1,'run',v,1,3).`
 		emptyPuzzle.Close()
 
 		var buf bytes.Buffer
-		err = run(dictFile.Name(), emptyPuzzle.Name(), false, &buf)
+		err = run(context.Background(), dictFile.Name(), emptyPuzzle.Name(), false, &buf)
 		if err == nil {
 			t.Error("Expected error for empty puzzle")
 		}
@@ -111,10 +116,10 @@ func TestTrieNode_Insert(t *testing.T) {
 		t.Error("Expected 'hello' to be found in trie")
 	}
 
-	// Test empty string
+	// The empty string is never a word
 	trie.Insert("")
-	if !trie.Search("") {
-		t.Error("Expected empty string to be found in trie")
+	if trie.Search("") {
+		t.Error("Expected empty string not to be stored in trie")
 	}
 
 	// Test unicode characters
@@ -172,7 +177,7 @@ s(100000005,1,'test word',n,1,9).`
 
 	// Test loading dictionary
 	trie := NewTrieNode()
-	wordCount, err := loadDictionary(tmpfile.Name(), trie, false)
+	wordCount, err := loadDictionary(context.Background(), tmpfile.Name(), trie, false)
 	if err != nil {
 		t.Fatalf("loadDictionary failed: %v", err)
 	}
@@ -199,7 +204,7 @@ s(100000005,1,'test word',n,1,9).`
 
 func TestLoadDictionary_FileNotFound(t *testing.T) {
 	trie := NewTrieNode()
-	_, err := loadDictionary("nonexistent.pl", trie, false)
+	_, err := loadDictionary(context.Background(), "nonexistent.pl", trie, false)
 	if err == nil {
 		t.Error("Expected error when loading non-existent file")
 	}
@@ -225,7 +230,7 @@ another invalid line`
 	}
 
 	trie := NewTrieNode()
-	wordCount, err := loadDictionary(tmpfile.Name(), trie, false)
+	wordCount, err := loadDictionary(context.Background(), tmpfile.Name(), trie, false)
 	if err != nil {
 		t.Fatalf("loadDictionary failed: %v", err)
 	}
@@ -368,28 +373,37 @@ func TestCheckInTrie(t *testing.T) {
 
 	permutations := []string{"hello", "world", "notfound", "hello"}
 
-	// Redirect stdout to capture output
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	checkInTrie(trie, permutations, false)
-
-	w.Close()
-	os.Stdout = oldStdout
-
-	buf, _ := io.ReadAll(r)
-	output := string(buf)
+	// Should return "hello" and "world" but not "notfound"
+	found := checkInTrie(trie, permutations, false)
+	if strings.Join(found, ",") != "hello,world,hello" {
+		t.Errorf("Expected [hello world hello], got %v", found)
+	}
+}
 
-	// Should contain "hello" and "world" but not "notfound"
-	if !strings.Contains(output, "hello") {
-		t.Error("Expected output to contain 'hello'")
+// BenchmarkRunEndToEnd measures a whole run as users see it: parsing a
+// WordNet file with generated forms, then solving a sample puzzle. The
+// dictionary is built from the bench_words fixture, one noun synset per
+// word, in a temporary directory that the benchmark removes afterwards.
+func BenchmarkRunEndToEnd(b *testing.B) {
+	words, err := readWordList("testdata/bench_words.txt")
+	if err != nil {
+		b.Fatal(err)
 	}
-	if !strings.Contains(output, "world") {
-		t.Error("Expected output to contain 'world'")
+	var dict strings.Builder
+	for i, word := range words {
+		fmt.Fprintf(&dict, "s(%d,1,'%s',n,1,0).\n", 100000000+i, word)
 	}
-	if strings.Contains(output, "notfound") {
-		t.Error("Expected output to not contain 'notfound'")
+	dictPath := filepath.Join(b.TempDir(), "wn_s.pl")
+	if err := os.WriteFile(dictPath, []byte(dict.String()), 0o600); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := run(context.Background(), dictPath, "samples/puzzle1.txt", false, io.Discard); err != nil {
+			b.Fatal(err)
+		}
 	}
 }
 
@@ -444,7 +458,7 @@ s(100000004,1,'run',v,1,3).`
 	}
 
 	trie := NewTrieNode()
-	_, err = loadDictionary(tmpfile.Name(), trie, false)
+	_, err = loadDictionary(context.Background(), tmpfile.Name(), trie, false)
 	if err != nil {
 		t.Fatalf("loadDictionary failed: %v", err)
 	}
@@ -495,7 +509,7 @@ s(100000005,1,'valid',a,1,5).`
 	}
 
 	trie := NewTrieNode()
-	wordCount, err := loadDictionary(tmpfile.Name(), trie, false)
+	wordCount, err := loadDictionary(context.Background(), tmpfile.Name(), trie, false)
 	if err != nil {
 		t.Fatalf("loadDictionary failed: %v", err)
 	}
@@ -583,6 +597,21 @@ func TestGeneratePlural(t *testing.T) {
 		{"fly", "flies"},
 		{"boy", "boys"},
 		{"key", "keys"},
+		{"hero", "heroes"},
+		{"potato", "potatoes"},
+		{"photo", "photos"},
+		{"radio", "radios"},
+		{"leaf", "leaves"},
+		{"knife", "knives"},
+		{"wolf", "wolves"},
+		{"roof", "roofs"},
+		{"cliff", "cliffs"},
+		{"golf", "golfs"},
+		{"mischief", "mischiefs"},
+		{"handkerchief", "handkerchiefs"},
+		{"bookshelf", "bookshelves"},
+		{"half", "halves"},
+		{"life", "lives"},
 	}
 
 	for _, tt := range tests {
@@ -607,7 +636,7 @@ func TestGenerateVerbForms(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		past, participle := generateVerbForms(tt.input)
+		past, participle, _, _ := generateVerbForms(tt.input)
 		if past != tt.expectedPast {
 			t.Errorf("generateVerbForms(%q) past = %q, expected %q", tt.input, past, tt.expectedPast)
 		}
@@ -655,24 +684,15 @@ func TestCheckInTrie_WithDebug(t *testing.T) {
 
 	permutations := []string{"hello", "notfound"}
 
-	// Redirect stdout to capture output
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	checkInTrie(trie, permutations, true)
-
-	w.Close()
-	os.Stdout = oldStdout
-
-	buf, _ := io.ReadAll(r)
-	output := string(buf)
+	logs := withLogger(t, slog.LevelDebug)
+	found := checkInTrie(trie, permutations, true)
+	output := logs.String()
 
-	// Should contain "hello" and debug message for "notfound"
-	if !strings.Contains(output, "hello") {
-		t.Error("Expected output to contain 'hello'")
+	// Should return "hello" and log a debug record for "notfound"
+	if len(found) != 1 || found[0] != "hello" {
+		t.Errorf("Expected [hello], got %v", found)
 	}
-	if !strings.Contains(output, "Not found in trie: notfound") {
+	if !strings.Contains(output, `msg="not found in trie" word=notfound`) {
 		t.Error("Expected debug output for 'notfound'")
 	}
 }
@@ -695,27 +715,20 @@ s(100000002,1,'word',v,1,4).`
 		t.Fatal(err)
 	}
 
-	// Redirect stdout to capture debug output
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
+	logs := withLogger(t, slog.LevelDebug)
 	trie := NewTrieNode()
-	wordCount, err := loadDictionary(tmpfile.Name(), trie, true)
-
-	w.Close()
-	os.Stdout = oldStdout
-
+	wordCount, err := loadDictionary(context.Background(), tmpfile.Name(), trie, true)
 	if err != nil {
 		t.Fatalf("loadDictionary failed: %v", err)
 	}
-
-	buf, _ := io.ReadAll(r)
-	output := string(buf)
+	output := logs.String()
 
 	// Verify debug output
-	if !strings.Contains(output, "Reading line:") {
-		t.Error("Expected debug output to contain 'Reading line:'")
+	if !strings.Contains(output, `msg="reading line" line=1`) {
+		t.Error("Expected debug output to contain the first line read")
+	}
+	if !strings.Contains(output, `msg="skipping unparsable line" line=2 text="invalid line"`) {
+		t.Error("Expected the malformed line reported with its line number")
 	}
 
 	// Verify words were loaded
@@ -770,7 +783,7 @@ func TestGeneratePlural_EdgeCases(t *testing.T) {
 
 func TestGenerateVerbForms_EdgeCases(t *testing.T) {
 	// Test single character
-	past, participle := generateVerbForms("a")
+	past, participle, _, _ := generateVerbForms("a")
 	if past != "aed" {
 		t.Errorf("generateVerbForms('a') past = %q, expected 'aed'", past)
 	}
@@ -779,7 +792,7 @@ func TestGenerateVerbForms_EdgeCases(t *testing.T) {
 	}
 
 	// Test word ending in 'e' with length > 1
-	past, participle = generateVerbForms("be")
+	past, participle, _, _ = generateVerbForms("be")
 	if past != "bed" {
 		t.Errorf("generateVerbForms('be') past = %q, expected 'bed'", past)
 	}
@@ -858,7 +871,7 @@ s(100000003,1,'at',n,1,2).`
 
 	// Load dictionary
 	trie := NewTrieNode()
-	wordCount, err := loadDictionary(dictFile.Name(), trie, false)
+	wordCount, err := loadDictionary(context.Background(), dictFile.Name(), trie, false)
 	if err != nil {
 		t.Fatalf("Failed to load dictionary: %v", err)
 	}
@@ -928,7 +941,7 @@ s(100000005,1,'sat',s,1,3).`
 	}
 
 	trie := NewTrieNode()
-	wordCount, err := loadDictionary(tmpfile.Name(), trie, false)
+	wordCount, err := loadDictionary(context.Background(), tmpfile.Name(), trie, false)
 	if err != nil {
 		t.Fatalf("loadDictionary failed: %v", err)
 	}
@@ -960,22 +973,9 @@ func TestCheckInTrie_EmptyPermutations(t *testing.T) {
 	trie := NewTrieNode()
 	trie.Insert("test")
 
-	// Redirect stdout
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	checkInTrie(trie, []string{}, false)
-
-	w.Close()
-	os.Stdout = oldStdout
-
-	buf, _ := io.ReadAll(r)
-	output := string(buf)
-
-	// Should produce no output for empty permutations
-	if strings.Contains(output, "test") {
-		t.Error("Expected no output for empty permutations")
+	// Should find nothing for empty permutations
+	if found := checkInTrie(trie, []string{}, false); len(found) != 0 {
+		t.Errorf("Expected no words for empty permutations, got %v", found)
 	}
 }
 
@@ -1025,7 +1025,7 @@ func TestLoadDictionary_ScannerError(t *testing.T) {
 	}
 
 	trie := NewTrieNode()
-	_, err = loadDictionary(tmpfile.Name(), trie, false)
+	_, err = loadDictionary(context.Background(), tmpfile.Name(), trie, false)
 	if err != nil {
 		t.Fatalf("loadDictionary should not fail on valid file: %v", err)
 	}
@@ -1121,7 +1121,7 @@ s(100000004,1,'MiXeD',n,1,5).`
 	}
 
 	trie := NewTrieNode()
-	_, err = loadDictionary(tmpfile.Name(), trie, false)
+	_, err = loadDictionary(context.Background(), tmpfile.Name(), trie, false)
 	if err != nil {
 		t.Fatalf("loadDictionary failed: %v", err)
 	}
@@ -1142,3 +1142,30 @@ s(100000004,1,'MiXeD',n,1,5).`
 		t.Error("Expected 'mixed' to not be in trie (was capitalized)")
 	}
 }
+
+func TestRun_SentinelErrors(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\n")
+	emptyPuzzlePath := writeTempFile(t, "empty.txt", "\n  \n")
+
+	tests := []struct {
+		name       string
+		dictionary string
+		puzzle     string
+		expected   error
+	}{
+		{"missing dictionary", "/nonexistent/dict.pl", puzzlePath, ErrDictionaryNotFound},
+		{"missing puzzle", dictPath, "/nonexistent/puzzle.txt", ErrPuzzleNotFound},
+		{"empty puzzle", dictPath, emptyPuzzlePath, ErrEmptyPuzzle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := run(context.Background(), tt.dictionary, tt.puzzle, false, &buf)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("run() error = %v, expected errors.Is(%v)", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// maxScoreTiles is the most tiles maxScore handles, one bit each in a
+// uint64 mask.
+const maxScoreTiles = 64
+
+// placement is a match pinned to specific tile positions, as a mask with
+// one bit per tile it uses.
+type placement struct {
+	result Result
+	mask   uint64
+}
+
+// placeMatches returns every way to place each match on distinct tile
+// positions. A match usually has one placement, more when the board repeats
+// one of its tiles.
+func placeMatches(tiles []string, matches []Result) []placement {
+	positions := make(map[string][]int)
+	for i, tile := range tiles {
+		positions[tile] = append(positions[tile], i)
+	}
+
+	var placed []placement
+	for _, m := range matches {
+		var place func(k int, mask uint64)
+		place = func(k int, mask uint64) {
+			if k == len(m.Tiles) {
+				placed = append(placed, placement{result: m, mask: mask})
+				return
+			}
+			for _, i := range positions[m.Tiles[k]] {
+				if bit := uint64(1) << i; mask&bit == 0 {
+					place(k+1, mask|bit)
+				}
+			}
+		}
+		place(0, 0)
+	}
+	return placed
+}
+
+// maxScore returns the highest total score of a set of matches that share
+// no tile, counting each word once, along with one such set in result
+// order. It searches tile by tile, either placing a word on the lowest free
+// tile or leaving that tile unused, and abandons a branch once even scoring
+// every free tile at the best points-per-tile rate could not beat the best
+// set found. Boards of more than maxScoreTiles tiles report false.
+func maxScore(tiles []string, matches []Result) (int, []Result, bool) {
+	if len(tiles) > maxScoreTiles {
+		return 0, nil, false
+	}
+
+	placed := placeMatches(tiles, matches)
+	byTile := make([][]placement, len(tiles))
+	rate := 0.0
+	for _, p := range placed {
+		byTile[bits.TrailingZeros64(p.mask)] = append(byTile[bits.TrailingZeros64(p.mask)], p)
+		rate = max(rate, float64(p.result.Score)/float64(len(p.result.Tiles)))
+	}
+	// Trying high scores first finds a strong set early, so the bound prunes more
+	for _, list := range byTile {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].result.Score > list[j].result.Score })
+	}
+
+	best, current := 0, 0
+	var bestSet, chosen []Result
+	usedWords := make(map[string]bool)
+
+	var search func(free uint64)
+	search = func(free uint64) {
+		if current > best {
+			best = current
+			bestSet = append(bestSet[:0], chosen...)
+		}
+		if free == 0 || float64(current)+rate*float64(bits.OnesCount64(free)) <= float64(best) {
+			return
+		}
+
+		lowest := bits.TrailingZeros64(free)
+		for _, p := range byTile[lowest] {
+			if p.mask&^free != 0 || usedWords[p.result.Word] {
+				continue
+			}
+			usedWords[p.result.Word] = true
+			chosen = append(chosen, p.result)
+			current += p.result.Score
+			search(free &^ p.mask)
+			current -= p.result.Score
+			chosen = chosen[:len(chosen)-1]
+			usedWords[p.result.Word] = false
+		}
+		search(free &^ (1 << lowest))
+	}
+
+	all := uint64(1)<<len(tiles) - 1
+	if len(tiles) == maxScoreTiles {
+		all = ^uint64(0)
+	}
+	search(all)
+
+	sortMatches(bestSet)
+	return best, bestSet, true
+}
+
+// printMaxScore reports the best score from words that share no tile and
+// the words that reach it.
+func printMaxScore(w io.Writer, tiles []string, matches []Result) {
+	score, words, ok := maxScore(tiles, matches)
+	if !ok {
+		fmt.Fprintf(w, "Max score: boards of more than %d tiles are not supported\n", maxScoreTiles)
+		return
+	}
+	fmt.Fprintf(w, "Max score: %d points from words that share no tile\n", score)
+	for _, r := range words {
+		fmt.Fprintf(w, "  %s (%s) %d\n", r.Word, strings.Join(r.Tiles, "|"), r.Score)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMaxScore(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"caterpillar", "cat", "cats", "at"} {
+		trie.Insert(word)
+	}
+	tiles := []string{"ca", "ter", "pil", "lar", "c", "at", "s"}
+	matches, _, _ := findMatches(context.Background(), trie, tiles, quartileMaxTiles, false, nil)
+
+	// caterpillar (8) and cats (4) share no tile; cat or at would block cats
+	score, words, ok := maxScore(tiles, matches)
+	if !ok || score != 12 {
+		t.Fatalf("maxScore() = %d, %v, expected 12", score, ok)
+	}
+	var got []string
+	for _, r := range words {
+		got = append(got, r.Word)
+	}
+	if strings.Join(got, ",") != "cats,caterpillar" {
+		t.Errorf("Expected cats,caterpillar, got %v", got)
+	}
+
+	var buf bytes.Buffer
+	printMaxScore(&buf, tiles, matches)
+	if !strings.Contains(buf.String(), "Max score: 12 points") || !strings.Contains(buf.String(), "caterpillar (ca|ter|pil|lar) 8") {
+		t.Errorf("Expected the score and its words, got:\n%s", buf.String())
+	}
+}
+
+func TestMaxScore_FullPartition(t *testing.T) {
+	trie := partitionTrie()
+	matches, _, _ := findMatches(context.Background(), trie, partitionBoard, quartileMaxTiles, false, nil)
+
+	if score, _, _ := maxScore(partitionBoard, matches); score != 40 {
+		t.Errorf("Expected five quartiles to score 40, got %d", score)
+	}
+}
+
+func TestMaxScore_WordCountsOnce(t *testing.T) {
+	// c|at and ca|t spell cat on disjoint tiles, but a word scores only once
+	matches := []Result{
+		{Word: "cat", Tiles: []string{"c", "at"}, Score: 2},
+		{Word: "cat", Tiles: []string{"ca", "t"}, Score: 2},
+	}
+	if score, _, _ := maxScore([]string{"c", "at", "ca", "t"}, matches); score != 2 {
+		t.Errorf("Expected cat to count once for 2 points, got %d", score)
+	}
+}
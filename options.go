@@ -0,0 +1,184 @@
+package main
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// options holds the settings that control a single solver run.
+type options struct {
+	dictionaryPath   string
+	dictionaryFormat string
+	puzzlePaths      []string // files, directories, or glob patterns
+	debug            bool
+	historyPath      string
+	interactive      bool
+	stats            bool
+	exactTiles       int
+	contains         string // keeps only words containing this substring
+	limit            int    // 0 prints every result
+	tileWeighted     bool   // explores high-yield first tiles first; see stopAfter
+	minTileLength    int    // 0 means defaultMinTileLength
+	maxTileLength    int    // 0 means defaultMaxTileLength
+	strictTiles      bool
+	maxTiles         int // 0 means quartileMaxTiles
+	anagram          bool
+	hint             bool
+	decompose        string // shows the tiles that build this word instead of solving
+	seed             int64  // 0 keeps randomized features deterministic
+	solution         bool
+	maxSolutions     int
+	allowTileReuse   bool
+	coverage         bool
+	tileStats        bool
+	maxScore         bool
+	prefixCache      bool
+	stem             bool
+	partialLastTile  bool
+	suggest          bool
+	allowlistPath    string
+	blocklistPath    string
+	variantsPath     string // adds the second word of each pair whose first is loaded
+	safe             bool
+	safeListPath     string // replaces the built-in --safe list; implies safe
+	timeout          time.Duration
+	lenient          bool
+	maxCandidates    int // 0 disables the cap
+	dryRun           bool
+	order            string // orderTiles, orderRarity, or orderFrequency; empty means orderTiles
+	frequencyPath    string
+	frequencies      frequencyTable // loaded from frequencyPath by runWithOptions
+	progress         io.Writer      // receives solve progress lines; nil disables them
+	diagnostics      io.Writer      // receives notes and warnings; nil means os.Stderr
+	format           string         // outputText, outputJSON, outputQuiet, or outputCSV; empty means outputText
+	scores           scoreTable     // nil means quartileScores
+	skipSatellites   bool
+	includeProper    bool
+	splitPhrases     bool
+	agentNouns       bool
+	noGeneratedForms bool
+	strictProlog     bool
+	exportDictPath   string       // writes the loaded dictionary here as a plain wordlist
+	cachePath        string       // writes the loaded dictionary here as a trie file
+	checkWord        string       // looks up this word instead of solving
+	showTiles        bool         // text output splits each word into its tiles
+	batch            *resultBatch // set by runCountingMatches when several puzzles share one JSON or CSV document
+}
+
+// maxTilesWarnThreshold is the --max-tiles value above which a run warns that
+// the factorial growth in arrangements will make solving slow.
+const maxTilesWarnThreshold = 6
+
+// tileLimit returns the most tiles a word may use in this run.
+func (o options) tileLimit() int {
+	if o.maxTiles == 0 {
+		return quartileMaxTiles
+	}
+	return o.maxTiles
+}
+
+// stopAfter returns how many words a search may stop after: --limit when
+// --tile-frequency-weighted asks for capped output to fill sooner, or 0 to
+// find every word. Filters and orders applied after the search, and
+// reports that need every word, such as --max-score and --coverage, rule
+// out stopping early.
+func (o options) stopAfter() int {
+	if !o.tileWeighted || o.limit <= 0 {
+		return 0
+	}
+	if o.exactTiles > 0 || o.contains != "" || (o.order != "" && o.order != orderTiles) {
+		return 0
+	}
+	if o.hint || o.coverage || o.tileStats || o.maxScore || o.suggest || o.historyPath != "" {
+		return 0
+	}
+	return o.limit
+}
+
+// tileLengthRange returns the fewest and most letters a tile should have.
+func (o options) tileLengthRange() (int, int) {
+	minLength, maxLength := o.minTileLength, o.maxTileLength
+	if minLength == 0 {
+		minLength = defaultMinTileLength
+	}
+	if maxLength == 0 {
+		maxLength = defaultMaxTileLength
+	}
+	return minLength, maxLength
+}
+
+// loadOptions returns the dictionary loading settings from the options.
+func (o options) loadOptions() loadOptions {
+	return loadOptions{
+		debug:            o.debug,
+		skipSatellites:   o.skipSatellites,
+		includeProper:    o.includeProper,
+		splitPhrases:     o.splitPhrases,
+		agentNouns:       o.agentNouns,
+		noGeneratedForms: o.noGeneratedForms,
+		strictProlog:     o.strictProlog,
+	}
+}
+
+// editsDictionary reports whether the run changes the loaded words, with
+// --allowlist, --variants, --blocklist, or --safe.
+func (o options) editsDictionary() bool {
+	return o.allowlistPath != "" || o.variantsPath != "" || o.blocklistPath != "" || o.safe || o.safeListPath != ""
+}
+
+// exportOnly reports whether the run only exports the dictionary, which is
+// when --export-dict or a trie cache path is given with no puzzle to solve.
+func (o options) exportOnly() bool {
+	return (o.exportDictPath != "" || o.cachePath != "") && len(o.puzzlePaths) == 0 && !o.interactive
+}
+
+// rng returns the random source for randomized features, seeded from
+// --seed so output can be reproduced, or nil when no seed is set.
+func (o options) rng() *rand.Rand {
+	if o.seed == 0 {
+		return nil
+	}
+	return rand.New(rand.NewSource(o.seed))
+}
+
+// textOutput reports whether results are printed for people rather than
+// other programs, which is when progress notes and headers are shown.
+func (o options) textOutput() bool {
+	return o.format == "" || o.format == outputText
+}
+
+// notices returns where warnings and notes about a run go: stderr unless
+// diagnostics is set, so stdout holds only results and stays parseable
+// when piped.
+func (o options) notices() io.Writer {
+	if o.diagnostics != nil {
+		return o.diagnostics
+	}
+	return os.Stderr
+}
+
+// reports returns where text reports such as --stats, --coverage, and
+// --hint are written: w, alongside the results, for text output, or the
+// notices writer otherwise, so JSON, CSV, and quiet output stay parseable.
+func (o options) reports(w io.Writer) io.Writer {
+	if o.textOutput() {
+		return w
+	}
+	return o.notices()
+}
+
+// printer returns the Printer for the configured format. runWithOptions
+// rejects unknown formats up front, so the text fallback is never reached
+// from the command line.
+func (o options) printer() Printer {
+	if o.showTiles && o.textOutput() {
+		return textPrinter{showTiles: true}
+	}
+	printer, err := newPrinter(o.format)
+	if err != nil {
+		return textPrinter{}
+	}
+	return printer
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Output formats accepted by --format.
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputQuiet = "quiet"
+	outputCSV   = "csv"
+)
+
+// Printer writes solver results in one output format.
+type Printer interface {
+	PrintResults(w io.Writer, results []Result) error
+}
+
+// newPrinter returns the Printer for an output format. An empty format
+// means text.
+func newPrinter(format string) (Printer, error) {
+	switch format {
+	case "", outputText:
+		return textPrinter{}, nil
+	case outputJSON:
+		return jsonPrinter{}, nil
+	case outputQuiet:
+		return quietPrinter{}, nil
+	case outputCSV:
+		return csvPrinter{}, nil
+	}
+	return nil, fmt.Errorf("--format must be %q, %q, %q, or %q, got %q", outputText, outputJSON, outputQuiet, outputCSV, format)
+}
+
+// resolveFormat applies --quiet, shorthand for --format quiet, to the
+// --format value. It is an error to combine --quiet with another format.
+func resolveFormat(format string, quiet bool) (string, error) {
+	if !quiet {
+		return format, nil
+	}
+	if format != "" && format != outputText && format != outputQuiet {
+		return "", fmt.Errorf("--quiet cannot be combined with --format %s", format)
+	}
+	return outputQuiet, nil
+}
+
+// textPrinter writes one numbered, colored line per result. With showTiles
+// set, each word is split into the tiles that build it, such as "ca|st|le".
+// Words matched only by --stem are followed by the base word they inflect,
+// and ones matched by --allow-partial-last-tile by the word they nearly spell.
+type textPrinter struct {
+	showTiles bool
+}
+
+func (p textPrinter) PrintResults(w io.Writer, results []Result) error {
+	for i, r := range results {
+		word := colorf(Green, "%s", r.Word)
+		if p.showTiles && len(r.Tiles) > 0 {
+			word = tileBreakdown(r.Tiles)
+		}
+		if r.Stem != "" {
+			word += colorf(Gray, " (not in dictionary; stem of %s)", r.Stem)
+		}
+		if r.Near != "" {
+			word += colorf(Gray, " (not in dictionary; near %s)", r.Near)
+		}
+		if _, err := fmt.Fprintln(w, colorf(Gray, "%2d. ", i+1)+word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tileColors alternate between neighbouring tiles so each one stands out.
+var tileColors = []string{Green, Cyan}
+
+// tileBreakdown joins tiles with gray "|" separators, coloring them in turn.
+func tileBreakdown(tiles []string) string {
+	parts := make([]string, len(tiles))
+	for i, tile := range tiles {
+		parts[i] = colorf(tileColors[i%len(tileColors)], "%s", tile)
+	}
+	return strings.Join(parts, colorf(Gray, "|"))
+}
+
+// jsonPrinter writes the results as an indented JSON array, one object per
+// result with its word, tiles, and score. No results is an empty array.
+type jsonPrinter struct{}
+
+func (jsonPrinter) PrintResults(w io.Writer, results []Result) error {
+	if results == nil {
+		results = []Result{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// quietPrinter writes only the words, lowercased, one per line, for piping
+// into other tools.
+type quietPrinter struct{}
+
+func (quietPrinter) PrintResults(w io.Writer, results []Result) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintln(w, strings.ToLower(r.Word)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvPrinter writes a header row followed by one word,tileCount,score,tiles
+// row per result. The tiles are joined with "|" and quoted as needed.
+type csvPrinter struct{}
+
+// csvHeader names the columns csvRow fills.
+var csvHeader = []string{"word", "tileCount", "score", "tiles"}
+
+// csvRow returns the CSV fields for one result.
+func csvRow(r Result) []string {
+	return []string{r.Word, strconv.Itoa(len(r.Tiles)), strconv.Itoa(r.Score), strings.Join(r.Tiles, "|")}
+}
+
+func (csvPrinter) PrintResults(w io.Writer, results []Result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := writer.Write(csvRow(r)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// puzzleResults is one puzzle's entry in the JSON document a resultBatch
+// prints.
+type puzzleResults struct {
+	Puzzle  string   `json:"puzzle"`
+	Results []Result `json:"results"`
+}
+
+// resultBatch prints the results of a run over several puzzles as a single
+// JSON or CSV document, so the output of one run parses as a whole: JSON is
+// one array of {"puzzle", "results"} objects and CSV has one header row with
+// a leading puzzle column. Each puzzle is written as soon as it is solved,
+// and finish closes the document.
+type resultBatch struct {
+	format  string
+	puzzle  string // names the puzzle whose results print writes next
+	printed int
+}
+
+// newResultBatch returns a resultBatch for a run over puzzles puzzle files
+// in format, or nil when each puzzle's results can be printed on their own:
+// for a single puzzle, and for text and quiet output.
+func newResultBatch(format string, puzzles int) *resultBatch {
+	if puzzles < 2 || (format != outputJSON && format != outputCSV) {
+		return nil
+	}
+	return &resultBatch{format: format}
+}
+
+// print writes the results of the current puzzle.
+func (b *resultBatch) print(w io.Writer, results []Result) error {
+	defer func() { b.printed++ }()
+	if b.format == outputJSON {
+		if results == nil {
+			results = []Result{}
+		}
+		data, err := json.MarshalIndent(puzzleResults{Puzzle: b.puzzle, Results: results}, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		separator := ",\n"
+		if b.printed == 0 {
+			separator = "[\n"
+		}
+		_, err = fmt.Fprintf(w, "%s  %s", separator, data)
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if b.printed == 0 {
+		if err := writer.Write(append([]string{"puzzle"}, csvHeader...)); err != nil {
+			return err
+		}
+	}
+	for _, r := range results {
+		if err := writer.Write(append([]string{b.puzzle}, csvRow(r)...)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// finish ends the document, which is still well formed when no puzzle was
+// printed.
+func (b *resultBatch) finish(w io.Writer) error {
+	if b.format == outputCSV {
+		if b.printed == 0 {
+			return b.print(w, nil)
+		}
+		return nil
+	}
+	if b.printed == 0 {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+	_, err := fmt.Fprint(w, "\n]\n")
+	return err
+}
+
+// printResults prints the results with the configured Printer, or into the
+// run's resultBatch when there is one, keeping only the first --limit of
+// them. Text output notes how many were left out on the notices writer.
+func printResults(w io.Writer, results []Result, opts options) error {
+	shown := results
+	if opts.limit > 0 && len(shown) > opts.limit {
+		shown = shown[:opts.limit]
+	}
+	write := opts.printer().PrintResults
+	if opts.batch != nil {
+		write = opts.batch.print
+	}
+	if err := write(w, shown); err != nil {
+		return err
+	}
+	if len(shown) < len(results) && opts.textOutput() {
+		fmt.Fprintf(opts.notices(), "Showing %d of %d words (raise --limit to see more)\n", len(shown), len(results))
+	}
+	return nil
+}
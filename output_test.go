@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var printerResults = []Result{
+	{Word: "at", Tiles: []string{"at"}, Score: 1},
+	{Word: "cat", Tiles: []string{"c", "at"}, Score: 2},
+}
+
+func TestTextPrinter(t *testing.T) {
+	withColor(t, false)
+
+	var buf bytes.Buffer
+	if err := (textPrinter{}).PrintResults(&buf, printerResults); err != nil {
+		t.Fatal(err)
+	}
+	if want := " 1. at\n 2. cat\n"; buf.String() != want {
+		t.Errorf("text output = %q, expected %q", buf.String(), want)
+	}
+}
+
+func TestTextPrinter_ShowTiles(t *testing.T) {
+	withColor(t, false)
+
+	var buf bytes.Buffer
+	results := append(printerResults, Result{Word: "castle", Tiles: []string{"ca", "st", "le"}, Score: 4})
+	if err := (textPrinter{showTiles: true}).PrintResults(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	if want := " 1. at\n 2. c|at\n 3. ca|st|le\n"; buf.String() != want {
+		t.Errorf("text output = %q, expected %q", buf.String(), want)
+	}
+
+	// Only text output is split; other formats ignore the setting
+	if _, ok := (options{showTiles: true, format: outputQuiet}).printer().(quietPrinter); !ok {
+		t.Error("Expected --show-tiles to leave quiet output unchanged")
+	}
+}
+
+func TestJSONPrinter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (jsonPrinter{}).PrintResults(&buf, printerResults); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"word": "cat"`) {
+		t.Errorf("Expected lowercase JSON keys, got %s", buf.String())
+	}
+
+	var decoded []Result
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	if !reflect.DeepEqual(decoded, printerResults) {
+		t.Errorf("Decoded %+v, expected %+v", decoded, printerResults)
+	}
+
+	buf.Reset()
+	if err := (jsonPrinter{}).PrintResults(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected an empty array for no results, got %q", buf.String())
+	}
+}
+
+func TestQuietPrinter(t *testing.T) {
+	withColor(t, true)
+
+	var buf bytes.Buffer
+	if err := (quietPrinter{}).PrintResults(&buf, printerResults); err != nil {
+		t.Fatal(err)
+	}
+	if want := "at\ncat\n"; buf.String() != want {
+		t.Errorf("quiet output = %q, expected %q", buf.String(), want)
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+	for format, want := range map[string]Printer{
+		"":          textPrinter{},
+		outputText:  textPrinter{},
+		outputJSON:  jsonPrinter{},
+		outputQuiet: quietPrinter{},
+		outputCSV:   csvPrinter{},
+	} {
+		got, err := newPrinter(format)
+		if err != nil || got != want {
+			t.Errorf("newPrinter(%q) = %T, %v, expected %T", format, got, err, want)
+		}
+	}
+	if _, err := newPrinter("xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestRun_JSONFormatIsParseable(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\n")
+
+	var buf bytes.Buffer
+	err := runWithOptions(context.Background(), options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, format: outputJSON}, &buf)
+	if err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+
+	var decoded []Result
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected only JSON on stdout, got %q: %v", buf.String(), err)
+	}
+	if len(decoded) != 1 || decoded[0].Word != "cat" {
+		t.Errorf("Expected [cat], got %+v", decoded)
+	}
+}
+
+func TestCSVPrinter(t *testing.T) {
+	results := append(printerResults, Result{Word: "a,b", Tiles: []string{"a,", "b"}, Score: 2})
+
+	var buf bytes.Buffer
+	if err := (csvPrinter{}).PrintResults(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(rows) != len(results)+1 {
+		t.Fatalf("Expected a header and %d rows, got %v", len(results), rows)
+	}
+	if want := []string{"word", "tileCount", "score", "tiles"}; !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("header = %v, expected %v", rows[0], want)
+	}
+	if want := []string{"cat", "2", "2", "c|at"}; !reflect.DeepEqual(rows[2], want) {
+		t.Errorf("cat row = %v, expected %v", rows[2], want)
+	}
+	// Commas inside a field survive quoting
+	if want := []string{"a,b", "2", "2", "a,|b"}; !reflect.DeepEqual(rows[3], want) {
+		t.Errorf("quoted row = %v, expected %v", rows[3], want)
+	}
+}
+
+func TestRun_Limit(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "cat\ncats\nat\nsat\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\ns\n")
+
+	var buf bytes.Buffer
+	found, err := runCountingMatches(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		limit:          2,
+		format:         outputQuiet,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("runCountingMatches() error = %v", err)
+	}
+	if lines := strings.Fields(buf.String()); len(lines) != 2 {
+		t.Errorf("Expected 2 printed results, got %v", lines)
+	}
+	if found != 4 {
+		t.Errorf("Expected the full match count of 4, got %d", found)
+	}
+}
+
+func TestPrintResults_LimitNote(t *testing.T) {
+	results := []Result{{Word: "at"}, {Word: "cat"}, {Word: "sat"}}
+
+	var buf, notes bytes.Buffer
+	if err := printResults(&buf, results, options{limit: 1, diagnostics: &notes}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(notes.String(), "Showing 1 of 3 words") {
+		t.Errorf("Expected the note to report the full total, got:\n%s", notes.String())
+	}
+
+	notes.Reset()
+	if err := printResults(&buf, results, options{limit: 3, diagnostics: &notes}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "Showing") {
+		t.Errorf("Expected no note when nothing is left out, got:\n%s", buf.String())
+	}
+}
+
+func TestRun_QuietOutputIsOnlyWords(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "cat\ncats\nat\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\ns\n")
+
+	var buf bytes.Buffer
+	if err := runWithOptions(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		format:         outputQuiet,
+	}, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	if want := "at\ncat\ncats\n"; buf.String() != want {
+		t.Errorf("quiet output = %q, expected exactly %q", buf.String(), want)
+	}
+}
+
+func TestResolveFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		quiet   bool
+		want    string
+		wantErr bool
+	}{
+		{outputText, false, outputText, false},
+		{outputJSON, false, outputJSON, false},
+		{outputText, true, outputQuiet, false},
+		{outputQuiet, true, outputQuiet, false},
+		{outputJSON, true, "", true},
+	}
+	for _, tt := range tests {
+		got, err := resolveFormat(tt.format, tt.quiet)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("resolveFormat(%q, %v) = (%q, %v), expected %q (error %v)", tt.format, tt.quiet, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRun_DiagnosticsOnStderr(t *testing.T) {
+	withColor(t, false)
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat!\nx\n")
+
+	var stdout, stderr bytes.Buffer
+	opts := options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		lenient:        true,
+		debug:          true,
+		diagnostics:    &stderr,
+	}
+	if err := runWithOptions(context.Background(), opts, &stdout); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+
+	if want := " 1. cat\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, expected only the results %q", stdout.String(), want)
+	}
+	for _, note := range []string{"Loaded 2 words", "Warning: line 2", "Warning: tile \"x\""} {
+		if !strings.Contains(stderr.String(), note) {
+			t.Errorf("Expected %q on stderr, got:\n%s", note, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// progressInterval is the least time between two progress lines.
+const progressInterval = 250 * time.Millisecond
+
+// progressCheckEvery is how many candidates pass between clock checks, so
+// throttling costs almost nothing on the hot search path.
+const progressCheckEvery = 1024
+
+// progressReporter writes throttled progress lines during a solve. Each line
+// overwrites the previous one with a carriage return, so it is meant for a
+// terminal. A nil reporter is silent. It is safe for concurrent use by the
+// search workers.
+type progressReporter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	total    int
+	interval time.Duration
+	last     time.Time
+	checks   int
+}
+
+// newProgressReporter returns a reporter writing to w for a search of total
+// arrangements, or nil if w is nil.
+func newProgressReporter(w io.Writer, total int) *progressReporter {
+	if w == nil {
+		return nil
+	}
+	return &progressReporter{w: w, total: total, interval: progressInterval, last: time.Now()}
+}
+
+// update reports done of total arrangements covered, at most once per
+// interval.
+func (p *progressReporter) update(done int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checks++
+	if p.checks%progressCheckEvery != 0 {
+		return
+	}
+	if now := time.Now(); now.Sub(p.last) >= p.interval {
+		p.last = now
+		p.print(done)
+	}
+}
+
+// finish writes the final count and ends the progress line.
+func (p *progressReporter) finish(done int) {
+	if p == nil {
+		return
+	}
+	p.print(done)
+	fmt.Fprintln(p.w)
+}
+
+func (p *progressReporter) print(done int) {
+	percent := 100.0
+	if p.total > 0 {
+		percent = 100 * float64(done) / float64(p.total)
+	}
+	fmt.Fprintf(p.w, "\rProgress: %d/%d combinations (%.0f%%)", done, p.total, percent)
+}
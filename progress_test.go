@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRun_ProgressWriter(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\nx\n")
+
+	var out, progress bytes.Buffer
+	err := runWithOptions(context.Background(), options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, progress: &progress}, &out)
+	if err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+
+	if !strings.Contains(progress.String(), "Progress: 15/15 combinations (100%)") {
+		t.Errorf("Expected a final progress line covering every combination, got %q", progress.String())
+	}
+	if strings.Contains(out.String(), "Progress:") {
+		t.Error("Expected progress to stay out of the results output")
+	}
+}
+
+func TestProgressReporter_Throttles(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressReporter(&buf, 10*progressCheckEvery)
+	p.interval = 0
+
+	for done := 1; done <= 3*progressCheckEvery; done++ {
+		p.update(done)
+	}
+	if got := strings.Count(buf.String(), "Progress:"); got != 3 {
+		t.Errorf("Expected one line per %d updates (3), got %d: %q", progressCheckEvery, got, buf.String())
+	}
+}
+
+func TestProgressReporter_NilIsSilent(t *testing.T) {
+	p := newProgressReporter(nil, 10)
+	if p != nil {
+		t.Fatal("Expected a nil reporter for a nil writer")
+	}
+	p.update(1)
+	p.finish(1)
+
+	// The search tolerates a nil reporter
+	trie := NewTrieNode()
+	trie.Insert("cat")
+	if _, _, err := findMatches(context.Background(), trie, []string{"c", "at"}, 4, false, nil); err != nil {
+		t.Errorf("findMatches() error = %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// parseWordNetFact is the --strict-prolog alternative to parseWordNetLine.
+// Instead of searching the line with a regular expression it reads it as
+// Prolog: a single s/6 fact ending in a period, with a synset id, an
+// integer, a quoted word, a part-of-speech atom, and two more integers.
+// Layout and comments are allowed between tokens and the word may use
+// backslash escapes as well as doubled apostrophes, so facts written by
+// other Prolog tools parse; a fact that is commented out, wrapped in another term, or followed by more
+// than a comment is rejected rather than partly matched.
+func parseWordNetFact(line string) (word, partOfSpeech string, ok bool) {
+	p := prologTokens{text: line}
+	if p.name() != "s" || !p.punct('(') {
+		return "", "", false
+	}
+	if !p.synsetID() || !p.punct(',') || !p.integer() || !p.punct(',') {
+		return "", "", false
+	}
+	word, ok = p.quoted()
+	if !ok || !p.punct(',') {
+		return "", "", false
+	}
+	partOfSpeech = p.name()
+	if len(partOfSpeech) != 1 || !strings.Contains("nvasr", partOfSpeech) {
+		return "", "", false
+	}
+	if !p.punct(',') || !p.integer() || !p.punct(',') || !p.integer() || !p.punct(')') || !p.punct('.') {
+		return "", "", false
+	}
+	if p.skipLayout(); p.pos != len(p.text) {
+		return "", "", false
+	}
+
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return "", "", false
+	}
+	return word, partOfSpeech, true
+}
+
+// prologTokens reads the tokens of one line of Prolog source. Each method
+// skips leading layout and comments, consumes a token if the text has one
+// of its kind next, and otherwise leaves the position for the caller to
+// give up on the line.
+type prologTokens struct {
+	text string
+	pos  int
+}
+
+// skipLayout moves past whitespace, % line comments, and /* */ comments.
+func (p *prologTokens) skipLayout() {
+	for p.pos < len(p.text) {
+		rest := p.text[p.pos:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r' || rest[0] == '\n':
+			p.pos++
+		case rest[0] == '%':
+			p.pos = len(p.text)
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				p.pos = len(p.text)
+				return
+			}
+			p.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+// punct consumes the punctuation character c.
+func (p *prologTokens) punct(c byte) bool {
+	p.skipLayout()
+	if p.pos < len(p.text) && p.text[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// integer consumes an unsigned decimal integer.
+func (p *prologTokens) integer() bool {
+	p.skipLayout()
+	start := p.pos
+	for p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// synsetID consumes a synset id, an unsigned decimal integer that some
+// exports write as a quoted atom such as '100001740'.
+func (p *prologTokens) synsetID() bool {
+	p.skipLayout()
+	if p.pos >= len(p.text) || p.text[p.pos] != '\'' {
+		return p.integer()
+	}
+	id, ok := p.quoted()
+	return ok && id != "" && strings.Trim(id, "0123456789") == ""
+}
+
+// name consumes an unquoted atom, a lowercase letter followed by letters,
+// digits, and underscores, and returns it, or "" if there is none.
+func (p *prologTokens) name() string {
+	p.skipLayout()
+	start := p.pos
+	for p.pos < len(p.text) {
+		r, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		if p.pos == start && !unicode.IsLower(r) {
+			break
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			break
+		}
+		p.pos += size
+	}
+	return p.text[start:p.pos]
+}
+
+// quotedEscapes maps the backslash escapes accepted in a quoted atom to the
+// characters they stand for.
+var quotedEscapes = map[byte]byte{'\\': '\\', '\'': '\'', '"': '"', '`': '`', 'n': '\n', 't': '\t'}
+
+// quoted consumes a single-quoted atom and returns its unescaped text. A
+// quote inside it is doubled or escaped with a backslash.
+func (p *prologTokens) quoted() (string, bool) {
+	if !p.punct('\'') {
+		return "", false
+	}
+	var atom strings.Builder
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		switch {
+		case c == '\'' && strings.HasPrefix(p.text[p.pos:], "''"):
+			atom.WriteByte('\'')
+			p.pos += 2
+		case c == '\'':
+			p.pos++
+			return atom.String(), true
+		case c == '\\':
+			if p.pos+1 >= len(p.text) {
+				return "", false
+			}
+			escaped, known := quotedEscapes[p.text[p.pos+1]]
+			if !known {
+				return "", false
+			}
+			atom.WriteByte(escaped)
+			p.pos += 2
+		case c == '\n':
+			return "", false
+		default:
+			atom.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseWordNetFact(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		word         string
+		partOfSpeech string
+		ok           bool
+	}{
+		{"noun", "s(102084071,1,'dog',n,1,42).", "dog", "n", true},
+		{"verb", "s(201926311,1,'run',v,1,62).", "run", "v", true},
+		{"adjective", "s(300001740,1,'able',a,1,29).", "able", "a", true},
+		{"adjective satellite", "s(300004296,1,'eatable',s,1,0).", "eatable", "s", true},
+		{"adverb", "s(400002310,1,'quickly',r,1,22).", "quickly", "r", true},
+		{"quoted synset id", "s('100001740',1,'entity',n,1,11).", "entity", "n", true},
+		{"quoted synset id with spaces", "s( '100001740' ,1,'entity',n,1,11).", "entity", "n", true},
+		{"quoted synset id not a number", "s('entity',1,'entity',n,1,11).", "", "", false},
+		{"empty quoted synset id", "s('',1,'entity',n,1,11).", "", "", false},
+		{"doubled apostrophe", "s(104437670,1,'o''clock',r,1,0).", "o'clock", "r", true},
+		{"backslash apostrophe", `s(104437670,1,'o\'clock',r,1,0).`, "o'clock", "r", true},
+		{"spaces between arguments", "s(102084071, 1, 'dog', n, 1, 42) .", "dog", "n", true},
+		{"comment inside the fact", "s(102084071,1,/* sense */'dog',n,1,42).", "dog", "n", true},
+		{"trailing comment", "s(102084071,1,'dog',n,1,42). % a dog", "dog", "n", true},
+		{"commented out", "% s(102084071,1,'dog',n,1,42).", "", "", false},
+		{"nested in another term", "old(s(102084071,1,'dog',n,1,42)).", "", "", false},
+		{"two facts", "s(102084071,1,'dog',n,1,42). s(102121245,1,'cat',n,1,9).", "", "", false},
+		{"comma inside the word", "s(1,1,'a, n',n,1,1).", "a, n", "n", true},
+		{"no final period", "s(102084071,1,'dog',n,1,42)", "", "", false},
+		{"unknown escape", `s(102084071,1,'d\og',n,1,42).`, "", "", false},
+		{"unterminated word", "s(102084071,1,'dog,n,1,42).", "", "", false},
+		{"unknown part of speech", "s(102084071,1,'dog',x,1,42).", "", "", false},
+		{"blank word", "s(102084071,1,' ',n,1,42).", "", "", false},
+		{"empty line", "", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			word, partOfSpeech, ok := parseWordNetFact(tt.line)
+			if word != tt.word || partOfSpeech != tt.partOfSpeech || ok != tt.ok {
+				t.Errorf("parseWordNetFact(%q) = %q, %q, %v, expected %q, %q, %v",
+					tt.line, word, partOfSpeech, ok, tt.word, tt.partOfSpeech, tt.ok)
+			}
+		})
+	}
+}
+
+// TestParseWordNetFact_RegexMisparses covers lines the default parser gets
+// wrong and the strict parser gets right.
+func TestParseWordNetFact_RegexMisparses(t *testing.T) {
+	tests := []struct {
+		line string
+		want string // "" when the line holds no fact to load
+	}{
+		{"% s(102084071,1,'dog',n,1,42).", ""},
+		{"old(s(102084071,1,'dog',n,1,42)).", ""},
+		{"s(102084071, 1, 'dog', n, 1, 42).", "dog"},
+		{`s(104437670,1,'o\'clock',r,1,0).`, "o'clock"},
+	}
+	for _, tt := range tests {
+		loose, _, _ := parseWordNetLine(tt.line)
+		strict, _, _ := parseWordNetFact(tt.line)
+		if loose == tt.want {
+			t.Errorf("Expected parseWordNetLine(%q) to misparse, got %q", tt.line, loose)
+		}
+		if strict != tt.want {
+			t.Errorf("parseWordNetFact(%q) = %q, expected %q", tt.line, strict, tt.want)
+		}
+	}
+}
+
+func TestReadWordNet_StrictProlog(t *testing.T) {
+	dictionary := strings.Join([]string{
+		"s(102084071, 1, 'dog', n, 1, 42).",
+		"% s(102121245,1,'cat',n,1,9).",
+		"s(201926311,1,'run',v,1,62).",
+	}, "\n")
+
+	trie := NewTrieNode()
+	if _, err := readWordNet(context.Background(), strings.NewReader(dictionary), trie, loadOptions{strictProlog: true}); err != nil {
+		t.Fatalf("readWordNet() error = %v", err)
+	}
+	for word, want := range map[string]bool{"dog": true, "dogs": true, "run": true, "cat": false} {
+		if got := trie.Search(word); got != want {
+			t.Errorf("Search(%q) = %v, expected %v", word, got, want)
+		}
+	}
+}
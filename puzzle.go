@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// readPuzzle reads one tile per non-blank line from the puzzle file.
+// Each tile is checked by parseTile; see there for how lenient applies.
+func readPuzzle(puzzlePath string, lenient bool, w io.Writer) ([]string, error) {
+	puzzleFile, err := os.Open(puzzlePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening puzzle file %s: %w", puzzlePath, err)
+	}
+	defer puzzleFile.Close()
+
+	var tiles []string
+	scanner := newLineScanner(puzzleFile)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		tile, err := parseTile(scanner.Text(), lineNumber, lenient, w)
+		if err != nil {
+			return nil, fmt.Errorf("puzzle file %s: %w", puzzlePath, err)
+		}
+		if tile != "" {
+			tiles = append(tiles, tile)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading puzzle file %s: %w", puzzlePath, err)
+	}
+
+	if len(tiles) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyPuzzle, puzzlePath)
+	}
+
+	return tiles, nil
+}
+
+// parseTile trims a puzzle line and checks that what remains is only letters.
+// A tile with digits, punctuation, or inner spaces is rejected with
+// ErrInvalidTile naming the line. In lenient mode the offending characters
+// are stripped and a warning is written to w instead. Blank lines, and
+// lenient lines with no letters left, yield an empty tile. Tiles are
+// lowercased to match the dictionary, since tiles copied from a screenshot
+// are often capitalized; errors and warnings quote the line as typed.
+// Smart quotes, dashes, and invisible spaces are removed first by
+// normalizePunctuation, even in strict mode.
+func parseTile(line string, lineNumber int, lenient bool, w io.Writer) (string, error) {
+	tile := strings.TrimSpace(normalizePunctuation(line))
+
+	invalid := strings.IndexFunc(tile, func(r rune) bool { return !unicode.IsLetter(r) })
+	if invalid < 0 {
+		return strings.ToLower(tile), nil
+	}
+
+	if !lenient {
+		return "", fmt.Errorf("%w on line %d: %q contains non-letter characters", ErrInvalidTile, lineNumber, tile)
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, strings.ToLower(tile))
+	fmt.Fprintf(w, "Warning: line %d: stripped non-letter characters from %q, using %q\n", lineNumber, tile, cleaned)
+	return cleaned, nil
+}
+
+// pastedPunctuation maps the Unicode punctuation that text copied from iOS
+// or recognized from a screenshot tends to pick up. Curly quotes and dashes
+// are never part of a tile, so they are dropped; a no-break space becomes a
+// plain space so it is trimmed like one.
+var pastedPunctuation = strings.NewReplacer(
+	"\u2018", "", "\u2019", "", "\u201a", "", "\u201b", "", // single quotes
+	"\u201c", "", "\u201d", "", "\u201e", "", "\u201f", "", // double quotes
+	"\u2032", "", "\u2033", "", // primes
+	"\u2010", "", "\u2011", "", "\u2012", "", "\u2013", "", "\u2014", "", "\u2015", "", "\u2212", "", // dashes and minus
+	"\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "", // zero-width characters
+	"\u00a0", " ", "\u202f", " ",
+)
+
+// normalizePunctuation removes pasted smart punctuation from a puzzle line.
+func normalizePunctuation(line string) string {
+	return pastedPunctuation.Replace(line)
+}
+
+// Quartile tiles are fragments of two to four letters. A tile outside this
+// range usually means two tiles were merged or one was split when typing
+// the puzzle in.
+const (
+	defaultMinTileLength = 2
+	defaultMaxTileLength = 4
+)
+
+// checkTileLengths reports every tile whose letter count falls outside
+// minLength..maxLength. Normally each is a warning written to w; in strict
+// mode the first one is returned as an ErrTileLength error instead.
+func checkTileLengths(tiles []string, minLength, maxLength int, strict bool, w io.Writer) error {
+	for _, tile := range tiles {
+		length := utf8.RuneCountInString(tile)
+		if length >= minLength && length <= maxLength {
+			continue
+		}
+		if strict {
+			return fmt.Errorf("%w: %q has %d letters, outside %d-%d", ErrTileLength, tile, length, minLength, maxLength)
+		}
+		fmt.Fprintf(w, "Warning: tile %q has %d letter(s); Quartile tiles have %d-%d, so check for a typo\n", tile, length, minLength, maxLength)
+	}
+	return nil
+}
+
+// stringList is a flag.Value that collects every occurrence of a repeated flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// expandPuzzlePaths resolves each --puzzle argument to puzzle files. A glob
+// pattern expands to its matches and a directory to the files directly
+// inside it, skipping hidden files; both are sorted by name. It returns
+// ErrPuzzleNotFound if an argument names nothing or matches no files.
+func expandPuzzlePaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: no puzzle given", ErrPuzzleNotFound)
+	}
+
+	var files []string
+	for _, path := range paths {
+		if strings.ContainsAny(path, "*?[") {
+			matches, err := filepath.Glob(path)
+			if err != nil {
+				return nil, fmt.Errorf("puzzle pattern %s: %w", path, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%w: no files match %s", ErrPuzzleNotFound, path)
+			}
+			files = append(files, matches...)
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrPuzzleNotFound, path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("checking puzzle %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading puzzle directory %s: %w", path, err)
+		}
+		found := 0
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+			found++
+		}
+		if found == 0 {
+			return nil, fmt.Errorf("%w: no files in directory %s", ErrPuzzleNotFound, path)
+		}
+	}
+	return files, nil
+}
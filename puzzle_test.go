@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadPuzzle_Validation(t *testing.T) {
+	t.Run("digit in tile is rejected", func(t *testing.T) {
+		path := writeTempFile(t, "puzzle.txt", "ca\n\nt3\n")
+		var buf bytes.Buffer
+		_, err := readPuzzle(path, false, &buf)
+		if !errors.Is(err, ErrInvalidTile) {
+			t.Fatalf("Expected ErrInvalidTile, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "line 3") || !strings.Contains(err.Error(), `"t3"`) {
+			t.Errorf("Expected error to name the tile and line, got %v", err)
+		}
+	})
+
+	t.Run("surrounding whitespace is trimmed", func(t *testing.T) {
+		path := writeTempFile(t, "puzzle.txt", "ca \n\tt\n")
+		var buf bytes.Buffer
+		tiles, err := readPuzzle(path, false, &buf)
+		if err != nil {
+			t.Fatalf("readPuzzle() unexpected error: %v", err)
+		}
+		if strings.Join(tiles, ",") != "ca,t" {
+			t.Errorf("Expected tiles [ca t], got %v", tiles)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no warnings, got %q", buf.String())
+		}
+	})
+
+	t.Run("lenient strips bad characters", func(t *testing.T) {
+		path := writeTempFile(t, "puzzle.txt", "c-a\nt3\n42\n")
+		var buf bytes.Buffer
+		tiles, err := readPuzzle(path, true, &buf)
+		if err != nil {
+			t.Fatalf("readPuzzle() unexpected error: %v", err)
+		}
+		if strings.Join(tiles, ",") != "ca,t" {
+			t.Errorf("Expected tiles [ca t], got %v", tiles)
+		}
+		if !strings.Contains(buf.String(), `line 2: stripped non-letter characters from "t3", using "t"`) {
+			t.Errorf("Expected a warning naming the tile, got %q", buf.String())
+		}
+	})
+}
+
+func TestRun_UppercaseTiles(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'castle',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "CA\nSt\nLE\n")
+
+	var buf bytes.Buffer
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, format: outputQuiet}
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "castle\n") {
+		t.Errorf("Expected uppercase tiles to match castle, got %q", buf.String())
+	}
+}
+
+func TestRun_SmartPunctuationInTiles(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "caterpillar\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "\u2018ca\u2019\nter\u2013\n\u201cpil\u201d\n\u00a0lar\u200b\n")
+
+	var buf bytes.Buffer
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, format: outputQuiet}
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("Expected pasted punctuation to be normalized, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "caterpillar\n") {
+		t.Errorf("Expected normalized tiles to match caterpillar, got %q", buf.String())
+	}
+}
+
+func TestParseTile(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    string
+		wantErr bool
+	}{
+		{"qu", "qu", false},
+		{"  café ", "café", false},
+		{"QU", "qu", false},
+		{"Café", "café", false},
+		{"", "", false},
+		{"a b", "", true},
+		{"it's", "", true},
+		{"it\u2019s", "its", false},
+		{"\u201cQU\u201d", "qu", false},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		got, err := parseTile(tt.line, 1, false, &buf)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseTile(%q) = (%q, %v), expected (%q, error=%v)", tt.line, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRunInteractive_InvalidTile(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+
+	var buf, notices bytes.Buffer
+	if err := runInteractive(context.Background(), trie, loadSummary{}, options{diagnostics: &notices}, strings.NewReader("c\n4t\nat\n"), &buf); err != nil {
+		t.Fatalf("runInteractive() unexpected error: %v", err)
+	}
+	if !strings.Contains(notices.String(), "Error: invalid tile on line 2") || strings.Contains(buf.String(), "Error:") {
+		t.Errorf("Expected invalid tile to be reported on the notices writer, got:\n%s\nand on stdout:\n%s", notices.String(), buf.String())
+	}
+	if !strings.Contains(buf.String(), "Puzzle 1: c at") {
+		t.Errorf("Expected the valid tiles to be solved, got:\n%s", buf.String())
+	}
+}
+
+func TestRun_MultiplePuzzles(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).\ns(100000002,1,'dog',n,1,3).")
+	first := writeTempFile(t, "first.txt", "c\nat\n")
+	second := writeTempFile(t, "second.txt", "d\nog\n")
+
+	var buf, diagnostics bytes.Buffer
+	err := runWithOptions(context.Background(), options{dictionaryPath: dictPath, puzzlePaths: []string{first, second}, diagnostics: &diagnostics}, &buf)
+	if err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+
+	if strings.Count(diagnostics.String(), "Loading dictionary from:") != 1 {
+		t.Errorf("Expected the dictionary to load once, got:\n%s", diagnostics.String())
+	}
+	output := buf.String()
+	firstHeader := strings.Index(output, "=== "+first+" ===")
+	secondHeader := strings.Index(output, "=== "+second+" ===")
+	if firstHeader < 0 || secondHeader < firstHeader {
+		t.Fatalf("Expected both headers in order, got:\n%s", output)
+	}
+	firstResults, secondResults := output[firstHeader:secondHeader], output[secondHeader:]
+	if !strings.Contains(firstResults, "cat") || strings.Contains(firstResults, "dog") {
+		t.Errorf("Expected only cat under the first header, got:\n%s", firstResults)
+	}
+	if !strings.Contains(secondResults, "dog") || strings.Contains(secondResults, "cat") {
+		t.Errorf("Expected only dog under the second header, got:\n%s", secondResults)
+	}
+}
+
+func TestRun_MultiplePuzzlesOneDocument(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).\ns(100000002,1,'dog',n,1,3).")
+	first := writeTempFile(t, "first.txt", "c\nat\n")
+	second := writeTempFile(t, "second.txt", "d\nog\n")
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{first, second}, diagnostics: io.Discard}
+
+	var buf bytes.Buffer
+	opts.format = outputJSON
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	var puzzles []puzzleResults
+	if err := json.Unmarshal(buf.Bytes(), &puzzles); err != nil {
+		t.Fatalf("Expected one JSON document, got %v:\n%s", err, buf.String())
+	}
+	if len(puzzles) != 2 || puzzles[0].Puzzle != first || puzzles[1].Puzzle != second {
+		t.Fatalf("Expected one entry per puzzle in order, got %+v", puzzles)
+	}
+	if len(puzzles[0].Results) == 0 || puzzles[0].Results[len(puzzles[0].Results)-1].Word != "cat" {
+		t.Errorf("Expected cat among the first puzzle's results, got %+v", puzzles[0].Results)
+	}
+
+	buf.Reset()
+	opts.format = outputCSV
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected one CSV document, got %v", err)
+	}
+	if got := strings.Join(records[0], ","); got != "puzzle,word,tileCount,score,tiles" {
+		t.Errorf("Expected a header with a puzzle column, got %s", got)
+	}
+	for _, record := range records[1:] {
+		if record[0] == "puzzle" {
+			t.Errorf("Expected the header only once, got %v", records)
+		}
+		if want := map[string]string{"cat": first, "dog": second}[record[1]]; want != "" && record[0] != want {
+			t.Errorf("Expected %s under puzzle %s, got %s", record[1], want, record[0])
+		}
+	}
+}
+
+func TestRun_SinglePuzzleHasNoHeader(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\n")
+
+	var buf bytes.Buffer
+	if err := run(context.Background(), dictPath, puzzlePath, false, &buf); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "===") {
+		t.Errorf("Expected no header for a single puzzle, got:\n%s", buf.String())
+	}
+}
+
+func TestExpandPuzzlePaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.txt", "a.txt", ".hidden", "notes.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("ab\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := expandPuzzlePaths([]string{dir})
+	if err != nil {
+		t.Fatalf("expandPuzzlePaths(dir) error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "notes.md")}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expandPuzzlePaths(dir) = %v, expected %v", got, want)
+	}
+
+	got, err = expandPuzzlePaths([]string{filepath.Join(dir, "*.txt")})
+	if err != nil {
+		t.Fatalf("expandPuzzlePaths(glob) error = %v", err)
+	}
+	if strings.Join(got, ",") != strings.Join(want[:2], ",") {
+		t.Errorf("expandPuzzlePaths(glob) = %v, expected %v", got, want[:2])
+	}
+
+	for _, paths := range [][]string{
+		nil,
+		{filepath.Join(dir, "missing.txt")},
+		{filepath.Join(dir, "*.csv")},
+		{filepath.Join(dir, "sub")},
+	} {
+		if _, err := expandPuzzlePaths(paths); !errors.Is(err, ErrPuzzleNotFound) {
+			t.Errorf("expandPuzzlePaths(%v) error = %v, expected ErrPuzzleNotFound", paths, err)
+		}
+	}
+}
+
+func TestCheckTileLengths_Warning(t *testing.T) {
+	var buf bytes.Buffer
+	err := checkTileLengths([]string{"ca", "s", "tle", "castles"}, defaultMinTileLength, defaultMaxTileLength, false, &buf)
+	if err != nil {
+		t.Fatalf("Expected warnings only, got %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{`tile "s" has 1 letter(s)`, `tile "castles" has 7 letter(s)`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected a warning containing %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, `"ca"`) || strings.Contains(output, `"tle"`) {
+		t.Errorf("Expected no warning for in-range tiles, got:\n%s", output)
+	}
+}
+
+func TestRun_StrictTiles(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "castle\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nstl\ne\n")
+
+	err := runWithOptions(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		strictTiles:    true,
+	}, &bytes.Buffer{})
+	if !errors.Is(err, ErrTileLength) {
+		t.Fatalf("Expected ErrTileLength in strict mode, got %v", err)
+	}
+
+	// A wider range accepts the single-letter tile
+	err = runWithOptions(context.Background(), options{
+		dictionaryPath: dictPath,
+		puzzlePaths:    []string{puzzlePath},
+		strictTiles:    true,
+		minTileLength:  1,
+	}, &bytes.Buffer{})
+	if err != nil {
+		t.Errorf("Expected --min-tile-length 1 to accept the tile, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Result orderings accepted by --order.
+const (
+	orderTiles     = "tiles"
+	orderRarity    = "rarity"
+	orderFrequency = "frequency"
+)
+
+// letterRarity weights each letter by how rarely it appears in English words,
+// using Scrabble tile values. Letters not listed, such as accented ones,
+// weigh 1.
+var letterRarity = map[rune]int{
+	'a': 1, 'b': 3, 'c': 3, 'd': 2, 'e': 1, 'f': 4, 'g': 2, 'h': 4, 'i': 1,
+	'j': 8, 'k': 5, 'l': 1, 'm': 3, 'n': 1, 'o': 1, 'p': 3, 'q': 10, 'r': 1,
+	's': 1, 't': 1, 'u': 1, 'v': 4, 'w': 4, 'x': 8, 'y': 4, 'z': 10,
+}
+
+// rarityScore sums the rarity weights of the letters in word.
+func rarityScore(word string) int {
+	score := 0
+	for _, char := range word {
+		if weight, ok := letterRarity[char]; ok {
+			score += weight
+		} else {
+			score++
+		}
+	}
+	return score
+}
+
+// validateOrder returns an error if order is not a known --order value.
+// An empty order means the default tile ordering.
+func validateOrder(order string) error {
+	switch order {
+	case "", orderTiles, orderRarity, orderFrequency:
+		return nil
+	}
+	return fmt.Errorf("--order must be %q, %q, or %q, got %q", orderTiles, orderRarity, orderFrequency, order)
+}
+
+// sortMatchesByRarity orders matches like sortMatches, fewest tiles first,
+// but among words with the same tile count lists those with the highest
+// rarityScore first. Words with equal scores fall back to alphabetical and
+// then tile order, so the result is still deterministic.
+func sortMatchesByRarity(matches []Result) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if len(a.Tiles) != len(b.Tiles) {
+			return len(a.Tiles) < len(b.Tiles)
+		}
+		if ra, rb := rarityScore(a.Word), rarityScore(b.Word); ra != rb {
+			return ra > rb
+		}
+		if a.Word != b.Word {
+			return a.Word < b.Word
+		}
+		return strings.Join(a.Tiles, "|") < strings.Join(b.Tiles, "|")
+	})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRarityScore(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"", 0},
+		{"quiz", 22},
+		{"rate", 4},
+		{"café", 9},
+	}
+	for _, tt := range tests {
+		if got := rarityScore(tt.word); got != tt.want {
+			t.Errorf("rarityScore(%q) = %d, expected %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSortMatchesByRarity(t *testing.T) {
+	matches := []Result{
+		{Word: "rate", Tiles: []string{"ra", "te"}},
+		{Word: "quiz", Tiles: []string{"qu", "iz"}},
+		{Word: "a", Tiles: []string{"a"}},
+	}
+	sortMatchesByRarity(matches)
+
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Word)
+	}
+	// Tile count still comes first; quiz outranks rate among two-tile words
+	if want := "a,quiz,rate"; strings.Join(got, ",") != want {
+		t.Errorf("sortMatchesByRarity() order = %v, expected %s", got, want)
+	}
+}
+
+func TestRun_OrderRarity(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "quiz\nrate\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ra\nte\nqu\niz\n")
+
+	var buf bytes.Buffer
+	err := runWithOptions(context.Background(), options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, order: orderRarity}, &buf)
+	if err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	output := buf.String()
+	if quiz, rate := strings.Index(output, "quiz"), strings.Index(output, "rate"); quiz < 0 || rate < quiz {
+		t.Errorf("Expected quiz before rate, got:\n%s", output)
+	}
+
+	if err := runWithOptions(context.Background(), options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, order: "length"}, &buf); err == nil {
+		t.Error("Expected an error for an unknown --order")
+	}
+}
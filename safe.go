@@ -0,0 +1,27 @@
+package main
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// defaultOffensiveWords is the word list --safe removes when no
+// --safe-list is given.
+//
+//go:embed wordlists/offensive.txt
+var defaultOffensiveWords string
+
+// applySafeFilter deletes offensive words from the trie, using the list at
+// safeListPath or the embedded default list when it is empty, and returns
+// how many were present. Like a blocklist, only the listed spellings are
+// removed, so a list should include any inflections to block.
+func applySafeFilter(trie *TrieNode, safeListPath string) (int, error) {
+	if safeListPath != "" {
+		return applyBlocklist(trie, safeListPath)
+	}
+	words, err := scanWordList(strings.NewReader(defaultOffensiveWords))
+	if err != nil {
+		return 0, err
+	}
+	return deleteWords(trie, words), nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRun_Safe(t *testing.T) {
+	dictPath := writeTempFile(t, "words.txt", "damn\ndamp\n")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "da\nmn\nmp\n")
+
+	solve := func(opts options) []string {
+		t.Helper()
+		opts.dictionaryPath = dictPath
+		opts.puzzlePaths = []string{puzzlePath}
+		opts.format = outputQuiet
+		var buf bytes.Buffer
+		if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+			t.Fatalf("runWithOptions() error = %v", err)
+		}
+		return strings.Fields(buf.String())
+	}
+
+	if got := solve(options{}); strings.Join(got, ",") != "damn,damp" {
+		t.Errorf("Expected both words without --safe, got %v", got)
+	}
+	if got := solve(options{safe: true}); strings.Join(got, ",") != "damp" {
+		t.Errorf("Expected the listed word removed with --safe, got %v", got)
+	}
+
+	// A supplied list replaces the built-in one
+	listPath := writeTempFile(t, "list.txt", "damp\n")
+	if got := solve(options{safeListPath: listPath}); strings.Join(got, ",") != "damn" {
+		t.Errorf("Expected only the supplied list applied, got %v", got)
+	}
+}
+
+func TestDefaultOffensiveWords_Parse(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("shit")
+	trie.Insert("shirt")
+
+	removed, err := applySafeFilter(trie, "")
+	if err != nil {
+		t.Fatalf("applySafeFilter() error = %v", err)
+	}
+	if removed != 1 || trie.Search("shit") || !trie.Search("shirt") {
+		t.Errorf("Expected only the embedded list's word removed, got %d removed", removed)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// scoreTable maps the number of tiles in a word to the points it earns.
+// Tile counts missing from the table score 0.
+type scoreTable map[int]int
+
+// quartileScores is the official Quartile scoring.
+var quartileScores = scoreTable{1: 1, 2: 2, 3: 4, 4: 8}
+
+// scoreWord returns the points for a word built from tileCount tiles.
+func (s scoreTable) scoreWord(tileCount int) int {
+	return s[tileCount]
+}
+
+// parseScoreTable reads a --scores value such as "3=5,4=10". Each entry sets
+// the points for one tile count; counts not mentioned keep their Quartile
+// scores. Tile counts must be at least 1 and points must be non-negative.
+func parseScoreTable(spec string) (scoreTable, error) {
+	table := make(scoreTable, len(quartileScores))
+	for tileCount, points := range quartileScores {
+		table[tileCount] = points
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		countText, pointsText, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("--scores entry %q must look like TILES=POINTS", entry)
+		}
+		tileCount, err := strconv.Atoi(strings.TrimSpace(countText))
+		if err != nil || tileCount < 1 {
+			return nil, fmt.Errorf("--scores entry %q: tile count must be a whole number of at least 1", entry)
+		}
+		points, err := strconv.Atoi(strings.TrimSpace(pointsText))
+		if err != nil {
+			return nil, fmt.Errorf("--scores entry %q: points must be a whole number", entry)
+		}
+		if points < 0 {
+			return nil, fmt.Errorf("--scores entry %q: points must not be negative", entry)
+		}
+		table[tileCount] = points
+	}
+	return table, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseScoreTable(t *testing.T) {
+	table, err := parseScoreTable("3=5, 4=10,6=20")
+	if err != nil {
+		t.Fatalf("parseScoreTable() error = %v", err)
+	}
+	want := scoreTable{1: 1, 2: 2, 3: 5, 4: 10, 6: 20}
+	for tileCount, points := range want {
+		if got := table.scoreWord(tileCount); got != points {
+			t.Errorf("scoreWord(%d) = %d, expected %d", tileCount, got, points)
+		}
+	}
+	if quartileScores.scoreWord(4) != 8 {
+		t.Error("Expected parsing to leave the default table unchanged")
+	}
+
+	for _, spec := range []string{"4=-1", "4", "0=3", "x=1", "4=y"} {
+		if _, err := parseScoreTable(spec); err == nil {
+			t.Errorf("parseScoreTable(%q) expected an error", spec)
+		}
+	}
+}
+
+func TestSolveTiles_CustomScores(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"at", "cat", "cats"} {
+		trie.Insert(word)
+	}
+	tiles := []string{"c", "at", "s"}
+
+	results, _, _, _ := solveTiles(context.Background(), trie, tiles, options{})
+	if got := totalScore(results); got != 1+2+4 {
+		t.Errorf("Default total = %d, expected 7", got)
+	}
+
+	scores, err := parseScoreTable("1=0,2=10,3=100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, _, _, _ = solveTiles(context.Background(), trie, tiles, options{scores: scores})
+	if got := totalScore(results); got != 0+10+100 {
+		t.Errorf("Custom total = %d, expected 110", got)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// findMatches searches every arrangement of 1 to maxTiles tiles and returns
+// those that spell dictionary words, in the order defined by sortMatches. Arrangements are
+// built one tile at a time and abandoned as soon as the joined letters are
+// not a prefix of any dictionary word, or no unused tile begins with a
+// letter that continues them. The search is split across one
+// worker per CPU; see findMatchesWorkers.
+//
+// If ctx is cancelled the search stops early and returns the matches found so
+// far along with the context's error. A non-nil progress is updated as
+// arrangements are checked or pruned away.
+func findMatches(ctx context.Context, trie wordTrie, tiles []string, maxTiles int, debug bool, progress *progressReporter) ([]Result, Stats, error) {
+	matches, _, stats, err := findMatchesWorkers(ctx, trie, tiles, searchSettings{
+		maxTiles: maxTiles,
+		workers:  runtime.GOMAXPROCS(0),
+		debug:    debug,
+	}, progress)
+	return matches, stats, err
+}
+
+// searchSettings tune how findMatchesWorkers runs a search.
+type searchSettings struct {
+	maxTiles int
+	workers  int
+	// cachePrefixes memoizes trie lookups by their letters, since
+	// different tile sequences such as c|at and ca|t can spell the same
+	// prefix. Each tile sequence is visited once, so only those repeats
+	// hit; on typical boards that is a small share of lookups and the map
+	// costs more than the trie walks it saves, so it is off by default.
+	cachePrefixes bool
+	// stem also matches arrangements that are not words but inflect one,
+	// per stemBase
+	stem bool
+	// partialLastTile also matches arrangements whose last tile is one edit
+	// away from completing a word, per nearWord
+	partialLastTile bool
+	// stopAfter ends each worker's search once it has found this many
+	// words; 0 finds them all
+	stopAfter int
+	debug     bool
+}
+
+// findMatchesWorkers is findMatches with the search split among up to
+// settings.workers goroutines. Each takes the arrangements starting with
+// one tile at a time and walks the trie read-only, which is safe because
+// nothing modifies it once loaded. Sorting the combined matches makes the
+// output independent of how the work was divided.
+//
+// Arrangements matched only by settings.stem or settings.partialLastTile
+// are not dictionary words, so they come back unscored in loose, apart
+// from the real matches, and are left out of stats.Matches.
+func findMatchesWorkers(ctx context.Context, trie wordTrie, tiles []string, settings searchSettings, progress *progressReporter) (matches, loose []Result, stats Stats, err error) {
+	startTime := time.Now()
+	workers := max(1, min(settings.workers, len(tiles)))
+
+	first := make(chan int, len(tiles))
+	for i := range tiles {
+		first <- i
+	}
+	close(first)
+
+	// done counts arrangements checked or ruled out across every worker
+	var done atomic.Int64
+	searches := make([]*matchSearch, workers)
+	var wg sync.WaitGroup
+	for w := range searches {
+		s := &matchSearch{
+			ctx:      ctx,
+			trie:     trie,
+			tiles:    tiles,
+			maxTiles: settings.maxTiles,
+			stem:     settings.stem,
+			partial:  settings.partialLastTile,
+			limit:    settings.stopAfter,
+			debug:    settings.debug,
+			progress: progress,
+			done:     &done,
+			used:     make([]bool, len(tiles)),
+		}
+		if settings.cachePrefixes {
+			s.prefixes = make(map[string]trieCursor)
+		}
+		searches[w] = s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range first {
+				if s.err != nil {
+					return
+				}
+				s.visit(i, "")
+			}
+		}()
+	}
+	wg.Wait()
+	progress.finish(int(done.Load()))
+
+	var lookups, hits int
+	for _, s := range searches {
+		matches = append(matches, s.matches...)
+		loose = append(loose, s.loose...)
+		stats.Candidates += s.stats.Candidates
+		stats.Pruned += s.stats.Pruned
+		lookups += s.lookups
+		hits += s.hits
+		if err == nil {
+			err = s.err
+		}
+	}
+	sortMatches(matches)
+	sortMatches(loose)
+
+	if settings.cachePrefixes && settings.debug && lookups > 0 {
+		logger.Debug("prefix cache", "lookups", lookups, "hits", hits,
+			"hit_rate", fmt.Sprintf("%.1f%%", 100*float64(hits)/float64(lookups)))
+	}
+
+	stats.Matches = len(matches)
+	stats.SolveDuration = time.Since(startTime)
+	return matches, loose, stats, err
+}
+
+// matchSearch is one findMatches worker's depth-first search over tile
+// arrangements, with the matches and counts it has gathered.
+type matchSearch struct {
+	ctx      context.Context
+	trie     wordTrie
+	tiles    []string
+	maxTiles int
+	stem     bool
+	partial  bool
+	limit    int
+	debug    bool
+	progress *progressReporter
+	done     *atomic.Int64
+
+	used     []bool
+	sequence []string
+	matches  []Result
+	loose    []Result
+	stats    Stats
+	err      error
+
+	// prefixes maps letters already looked up to their trie node, or nil
+	// when no word begins with them; a nil map disables the cache
+	prefixes map[string]trieCursor
+	lookups  int
+	hits     int
+}
+
+// lookup returns the trie node reached by word, or nil if no dictionary
+// word begins with it, consulting the prefix cache when there is one.
+func (s *matchSearch) lookup(word string) trieCursor {
+	if s.prefixes == nil {
+		return s.trie.at(word)
+	}
+	s.lookups++
+	if node, ok := s.prefixes[word]; ok {
+		s.hits++
+		return node
+	}
+	node := s.trie.at(word)
+	s.prefixes[word] = node
+	return node
+}
+
+// stemBase returns the dictionary word that word inflects when stemming is
+// on, or "".
+func (s *matchSearch) stemBase(word string) string {
+	if !s.stem {
+		return ""
+	}
+	return stemBase(s.trie, word)
+}
+
+// nearWord returns a dictionary word that starts with prefix and continues
+// with text one edit away from tile, when partial last tiles are allowed and
+// prefix holds at least one tile, or "". Completions are tried in
+// alphabetical order and ones that drop the tile entirely are ignored.
+func (s *matchSearch) nearWord(prefix, tile string) string {
+	if !s.partial || prefix == "" {
+		return ""
+	}
+	node := s.lookup(prefix)
+	if node == nil {
+		return ""
+	}
+	for _, completion := range fuzzySearch(node, tile, 1) {
+		if completion != "" {
+			return prefix + completion
+		}
+	}
+	return ""
+}
+
+// lettersAvailable reports whether some unused tile begins with a letter
+// that continues node. When none does, no longer arrangement can spell a
+// word, so they are skipped before any is built. With --stem or
+// --allow-partial-last-tile a match need not continue the trie, so the
+// check always passes.
+func (s *matchSearch) lettersAvailable(node trieCursor) bool {
+	if s.stem || s.partial {
+		return true
+	}
+	for i, tile := range s.tiles {
+		if s.used[i] {
+			continue
+		}
+		if first, _ := utf8.DecodeRuneInString(tile); node.next(first) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// search extends prefix with every unused tile in turn.
+func (s *matchSearch) search(prefix string) {
+	for i := range s.tiles {
+		if s.err != nil {
+			return
+		}
+		if !s.used[i] {
+			s.visit(i, prefix)
+		}
+	}
+}
+
+// visit checks prefix extended by tile i and, while no word rules it out,
+// searches the longer arrangements that start with it.
+func (s *matchSearch) visit(i int, prefix string) {
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		return
+	}
+	if s.limit > 0 && len(s.matches) >= s.limit {
+		return
+	}
+
+	tile := s.tiles[i]
+	word := prefix + tile
+	s.sequence = append(s.sequence, tile)
+	s.stats.Candidates++
+
+	node := s.lookup(word)
+	if node != nil && node.isWord() {
+		s.matches = append(s.matches, Result{Word: word, Tiles: append([]string{}, s.sequence...), Score: scoreWord(len(s.sequence))})
+	} else if base := s.stemBase(word); base != "" {
+		s.loose = append(s.loose, Result{Word: word, Tiles: append([]string{}, s.sequence...), Stem: base})
+	} else if near := s.nearWord(prefix, tile); near != "" {
+		s.loose = append(s.loose, Result{Word: word, Tiles: append([]string{}, s.sequence...), Near: near})
+	} else if s.debug {
+		logger.Debug("not found in trie", "word", word)
+	}
+
+	// skipped counts arrangements never generated because a pruned prefix
+	// ruled them out, so progress can be measured against the full projection
+	skipped := 0
+	if len(s.sequence) < s.maxTiles {
+		s.used[i] = true
+		extend := node != nil && s.lettersAvailable(node)
+		if extend {
+			s.search(word)
+		}
+		s.used[i] = false
+		if !extend {
+			s.stats.Pruned++
+			if s.progress != nil {
+				skipped = projectCandidates(len(s.tiles)-len(s.sequence), s.maxTiles-len(s.sequence))
+			}
+		}
+	}
+	if s.progress != nil {
+		s.progress.update(int(s.done.Add(int64(1 + skipped))))
+	}
+
+	s.sequence = s.sequence[:len(s.sequence)-1]
+}
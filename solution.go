@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// quartile is a dictionary word spelled by exactly quartileMaxTiles tiles,
+// identified by their positions in the puzzle so repeated tiles stay distinct.
+type quartile struct {
+	word  string
+	tiles [quartileMaxTiles]int
+}
+
+// findQuartiles returns every arrangement of quartileMaxTiles distinct tiles
+// that spells a dictionary word, abandoning arrangements whose letters are
+// not a prefix of any word as findMatches does.
+func findQuartiles(trie wordTrie, tiles []string) []quartile {
+	var quartiles []quartile
+	var current quartile
+	used := make([]bool, len(tiles))
+
+	var search func(prefix string, depth int)
+	search = func(prefix string, depth int) {
+		for i, tile := range tiles {
+			if used[i] {
+				continue
+			}
+			word := prefix + tile
+			current.tiles[depth] = i
+			if depth == quartileMaxTiles-1 {
+				if trie.Search(word) {
+					current.word = word
+					quartiles = append(quartiles, current)
+				}
+				continue
+			}
+			if trie.HasPrefix(word) {
+				used[i] = true
+				search(word, depth+1)
+				used[i] = false
+			}
+		}
+	}
+	search("", 0)
+	return quartiles
+}
+
+// findSolution looks for a set of quartiles that uses every tile exactly
+// once, the complete answer to a board. It returns false if the tiles cannot
+// be partitioned, including when their count is not a multiple of
+// quartileMaxTiles.
+func findSolution(tiles []string, quartiles []quartile) ([]quartile, bool) {
+	solutions := findSolutions(tiles, quartiles, 1)
+	if len(solutions) == 0 {
+		return nil, false
+	}
+	return solutions[0], true
+}
+
+// findSolutions returns up to limit distinct partitions of the tiles into
+// quartiles, or every partition if limit is 0 or less. It is an exact-cover
+// search in the style of Algorithm X: each tile is a column that must be
+// covered exactly once and each quartile is a row covering four columns. At
+// each step it branches on the uncovered tile with the fewest quartiles still
+// fitting, so a tile no quartile can cover ends the branch immediately and
+// each partition is reached along a single path. Ties go to the lowest tile
+// index and quartiles are tried in order of their tile indices, so the
+// partitions and their order depend only on the board, never on the order
+// quartiles were passed in.
+func findSolutions(tiles []string, quartiles []quartile, limit int) [][]quartile {
+	if len(tiles) == 0 || len(tiles)%quartileMaxTiles != 0 {
+		return nil
+	}
+
+	// byTile[i] lists the quartiles that use tile i
+	byTile := make([][]quartile, len(tiles))
+	for _, q := range sortedQuartiles(quartiles) {
+		for _, i := range q.tiles {
+			byTile[i] = append(byTile[i], q)
+		}
+	}
+
+	covered := make([]bool, len(tiles))
+	var chosen []quartile
+	var solutions [][]quartile
+	// Repeated tiles let one set of words be built from different tile
+	// positions; those count as the same partition
+	seen := make(map[string]bool)
+
+	var search func() bool
+	search = func() bool {
+		column, fits := -1, 0
+		for i, c := range covered {
+			if c {
+				continue
+			}
+			n := 0
+			for _, q := range byTile[i] {
+				if !overlaps(q, covered) {
+					n++
+				}
+			}
+			if column < 0 || n < fits {
+				column, fits = i, n
+			}
+		}
+		if column < 0 {
+			key := solutionKey(tiles, chosen)
+			if !seen[key] {
+				seen[key] = true
+				solutions = append(solutions, append([]quartile{}, chosen...))
+			}
+			return limit > 0 && len(solutions) >= limit
+		}
+
+		for _, q := range byTile[column] {
+			if overlaps(q, covered) {
+				continue
+			}
+			setCovered(q, covered, true)
+			chosen = append(chosen, q)
+			done := search()
+			chosen = chosen[:len(chosen)-1]
+			setCovered(q, covered, false)
+			if done {
+				return true
+			}
+		}
+		return false
+	}
+	search()
+	return solutions
+}
+
+// findCovers is findSolutions for games that let one tile appear in several
+// words of a solution, though never twice in one word. It returns up to
+// limit distinct sets of quartiles that together use every tile at least
+// once, or every such set if limit is 0 or less. Only covers with the fewest
+// words are reported; larger ones would just add redundant words to a smaller
+// cover. The search starts at one word per four tiles, rounded up, and allows
+// one more word at a time until a cover is found, so a board whose words
+// must share tiles is covered with the extra words it needs. Like
+// findSolutions, it branches on the uncovered tile with the fewest quartiles
+// and orders its results the same deterministic way.
+func findCovers(tiles []string, quartiles []quartile, limit int) [][]quartile {
+	if len(tiles) == 0 {
+		return nil
+	}
+
+	byTile := make([][]quartile, len(tiles))
+	for _, q := range sortedQuartiles(quartiles) {
+		for _, i := range q.tiles {
+			byTile[i] = append(byTile[i], q)
+		}
+	}
+
+	for i := range byTile {
+		// A tile no quartile uses can never be covered
+		if len(byTile[i]) == 0 {
+			return nil
+		}
+	}
+
+	// Each word covers at least one new tile, so no cover needs more words
+	// than there are tiles
+	for maxWords := (len(tiles) + quartileMaxTiles - 1) / quartileMaxTiles; maxWords <= len(tiles); maxWords++ {
+		if covers := findCoversOf(tiles, byTile, maxWords, limit); len(covers) > 0 {
+			return covers
+		}
+	}
+	return nil
+}
+
+// findCoversOf returns up to limit distinct covers of at most maxWords
+// quartiles for findCovers, where byTile[i] lists the quartiles using tile i.
+func findCoversOf(tiles []string, byTile [][]quartile, maxWords, limit int) [][]quartile {
+	// uses counts how many chosen quartiles use each tile
+	uses := make([]int, len(tiles))
+	var chosen []quartile
+	var solutions [][]quartile
+	seen := make(map[string]bool)
+
+	var search func() bool
+	search = func() bool {
+		column, uncovered := -1, 0
+		for i, n := range uses {
+			if n > 0 {
+				continue
+			}
+			uncovered++
+			if column < 0 || len(byTile[i]) < len(byTile[column]) {
+				column = i
+			}
+		}
+		if column < 0 {
+			key := solutionKey(tiles, chosen)
+			if !seen[key] {
+				seen[key] = true
+				solutions = append(solutions, append([]quartile{}, chosen...))
+			}
+			return limit > 0 && len(solutions) >= limit
+		}
+
+		// Each remaining word covers at most four new tiles
+		remaining := maxWords - len(chosen)
+		if remaining*quartileMaxTiles < uncovered {
+			return false
+		}
+
+		for _, q := range byTile[column] {
+			for _, i := range q.tiles {
+				uses[i]++
+			}
+			chosen = append(chosen, q)
+			done := search()
+			chosen = chosen[:len(chosen)-1]
+			for _, i := range q.tiles {
+				uses[i]--
+			}
+			if done {
+				return true
+			}
+		}
+		return false
+	}
+	search()
+	return solutions
+}
+
+// sortedQuartiles returns a copy of quartiles ordered by their tile
+// indices, compared position by position, then by word.
+func sortedQuartiles(quartiles []quartile) []quartile {
+	sorted := slices.Clone(quartiles)
+	slices.SortFunc(sorted, func(a, b quartile) int {
+		if c := slices.Compare(a.tiles[:], b.tiles[:]); c != 0 {
+			return c
+		}
+		return strings.Compare(a.word, b.word)
+	})
+	return sorted
+}
+
+// solutionKey identifies a partition by its words and tile text, ignoring
+// the order the quartiles were chosen in and which copy of a repeated tile
+// each one used.
+func solutionKey(tiles []string, solution []quartile) string {
+	parts := make([]string, len(solution))
+	for i, r := range solutionResults(tiles, solution, nil) {
+		parts[i] = r.Word + ":" + strings.Join(r.Tiles, "|")
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+// overlaps reports whether q uses any tile already covered.
+func overlaps(q quartile, covered []bool) bool {
+	for _, i := range q.tiles {
+		if covered[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// setCovered marks every tile of q as covered or uncovered.
+func setCovered(q quartile, covered []bool, value bool) {
+	for _, i := range q.tiles {
+		covered[i] = value
+	}
+}
+
+// solutionResults converts a partition into Results in the order chosen,
+// scored by scores, or by the Quartile scores when scores is nil.
+func solutionResults(tiles []string, solution []quartile, scores scoreTable) []Result {
+	if scores == nil {
+		scores = quartileScores
+	}
+	results := make([]Result, len(solution))
+	for i, q := range solution {
+		sequence := make([]string, len(q.tiles))
+		for j, index := range q.tiles {
+			sequence[j] = tiles[index]
+		}
+		results[i] = Result{Word: q.word, Tiles: sequence, Score: scores.scoreWord(len(sequence))}
+	}
+	return results
+}
+
+// printSolutions reports complete partitions of the board into quartiles,
+// with each word's points under scores, or the Quartile scores when scores
+// is nil.
+func printSolutions(w io.Writer, tiles []string, solutions [][]quartile, scores scoreTable) {
+	if len(solutions) == 0 {
+		fmt.Fprintln(w, "Solution: no partition of every tile into quartiles found")
+		return
+	}
+	for n, solution := range solutions {
+		if len(solutions) == 1 {
+			fmt.Fprintln(w, "Solution:")
+		} else {
+			fmt.Fprintf(w, "Solution %d of %d:\n", n+1, len(solutions))
+		}
+		for _, r := range solutionResults(tiles, solution, scores) {
+			fmt.Fprintf(w, "  %s (%s) %d\n", r.Word, strings.Join(r.Tiles, "|"), r.Score)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// partitionBoard is a 20-tile board whose only partition into quartiles is
+// caterpillar, butterfly, hamburger, pentagons, and sophistry.
+var partitionBoard = []string{
+	"ca", "ter", "pil", "lar",
+	"bu", "tt", "er", "fly",
+	"ha", "mb", "ur", "ger",
+	"pe", "nt", "ago", "ns",
+	"so", "ph", "ist", "ry",
+}
+
+// partitionTrie holds the board's quartiles plus a decoy quartile whose
+// tiles come from four different answers, so choosing it strands the rest.
+func partitionTrie() *TrieNode {
+	trie := NewTrieNode()
+	for _, word := range []string{"caterpillar", "butterfly", "hamburger", "pentagons", "sophistry", "caernsso"} {
+		trie.Insert(word)
+	}
+	return trie
+}
+
+func TestFindSolution_UniquePartition(t *testing.T) {
+	trie := partitionTrie()
+	quartiles := findQuartiles(trie, partitionBoard)
+	if len(quartiles) != 6 {
+		t.Fatalf("Expected 6 quartiles including the decoy, got %d", len(quartiles))
+	}
+
+	solution, found := findSolution(partitionBoard, quartiles)
+	if !found {
+		t.Fatal("Expected the board to be partitioned")
+	}
+
+	var words []string
+	for _, q := range solution {
+		words = append(words, q.word)
+	}
+	slices.Sort(words)
+	want := []string{"butterfly", "caterpillar", "hamburger", "pentagons", "sophistry"}
+	if !slices.Equal(words, want) {
+		t.Errorf("Expected %v, got %v", want, words)
+	}
+}
+
+func TestFindSolution_NoPartition(t *testing.T) {
+	trie := partitionTrie()
+
+	// Dropping a tile leaves 19, which no set of quartiles can cover
+	tiles := partitionBoard[1:]
+	if _, found := findSolution(tiles, findQuartiles(trie, tiles)); found {
+		t.Error("Expected no partition of 19 tiles")
+	}
+
+	// Removing one answer from the dictionary strands its four tiles
+	trie.Delete("sophistry")
+	if _, found := findSolution(partitionBoard, findQuartiles(trie, partitionBoard)); found {
+		t.Error("Expected no partition once an answer is missing")
+	}
+}
+
+func TestFindSolutions_TwoPartitions(t *testing.T) {
+	// The eight tiles split into abcdijkl+efghmnop or abefijmn+cdghklop.
+	// abcdefgh is also a quartile, but no word covers the other four tiles.
+	tiles := []string{"ab", "cd", "ef", "gh", "ij", "kl", "mn", "op"}
+	trie := NewTrieNode()
+	for _, word := range []string{"abcdijkl", "efghmnop", "abefijmn", "cdghklop", "abcdefgh"} {
+		trie.Insert(word)
+	}
+	quartiles := findQuartiles(trie, tiles)
+
+	solutions := findSolutions(tiles, quartiles, 0)
+	if len(solutions) != 2 {
+		t.Fatalf("Expected exactly 2 partitions, got %d", len(solutions))
+	}
+
+	var keys []string
+	for _, solution := range solutions {
+		var words []string
+		for _, q := range solution {
+			words = append(words, q.word)
+		}
+		slices.Sort(words)
+		keys = append(keys, strings.Join(words, ","))
+	}
+	slices.Sort(keys)
+	want := []string{"abcdijkl,efghmnop", "abefijmn,cdghklop"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("Expected partitions %v, got %v", want, keys)
+	}
+
+	if capped := findSolutions(tiles, quartiles, 1); len(capped) != 1 {
+		t.Errorf("Expected --max-solutions 1 to stop after one partition, got %d", len(capped))
+	}
+}
+
+func TestFindSolutions_RepeatedTilesCountOnce(t *testing.T) {
+	// Swapping the two "ab" tiles builds the same words from the same text
+	tiles := []string{"ab", "ab", "cd", "cd", "ef", "ef", "gh", "gh"}
+	trie := NewTrieNode()
+	trie.Insert("abcdefgh")
+
+	if solutions := findSolutions(tiles, findQuartiles(trie, tiles), 0); len(solutions) != 1 {
+		t.Errorf("Expected one distinct partition, got %d", len(solutions))
+	}
+}
+
+func TestFindCovers_TileReuse(t *testing.T) {
+	// caterpillar and butterfly share the "ter" tile, so the board has no
+	// partition but is covered once tiles may be reused across words
+	tiles := []string{"ca", "ter", "pil", "lar", "bu", "t", "fly"}
+	trie := NewTrieNode()
+	for _, word := range []string{"caterpillar", "butterfly", "cater"} {
+		trie.Insert(word)
+	}
+	quartiles := findQuartiles(trie, tiles)
+
+	if solutions := findSolutions(tiles, quartiles, 0); len(solutions) != 0 {
+		t.Errorf("Expected no partition without reuse, got %d", len(solutions))
+	}
+
+	covers := findCovers(tiles, quartiles, 0)
+	if len(covers) != 1 {
+		t.Fatalf("Expected exactly 1 cover with reuse, got %d", len(covers))
+	}
+	var words []string
+	for _, q := range covers[0] {
+		words = append(words, q.word)
+	}
+	slices.Sort(words)
+	if got := strings.Join(words, ","); got != "butterfly,caterpillar" {
+		t.Errorf("Expected cover butterfly,caterpillar, got %s", got)
+	}
+}
+
+func TestFindCovers_TileReuseNeedsExtraWords(t *testing.T) {
+	// The last four tiles spell no word together, so the 20-tile board has
+	// no partition. Sharing the "er" tile, sophister and buttery cover them
+	// in a sixth word beyond the usual five.
+	tiles := append(slices.Clone(partitionBoard[:16]), "so", "ph", "ist", "y")
+	trie := NewTrieNode()
+	for _, word := range []string{"caterpillar", "butterfly", "hamburger", "pentagons", "sophister", "buttery"} {
+		trie.Insert(word)
+	}
+	quartiles := findQuartiles(trie, tiles)
+
+	if solutions := findSolutions(tiles, quartiles, 0); len(solutions) != 0 {
+		t.Errorf("Expected no partition without reuse, got %d", len(solutions))
+	}
+
+	covers := findCovers(tiles, quartiles, 0)
+	if len(covers) != 1 {
+		t.Fatalf("Expected exactly 1 cover with reuse, got %d", len(covers))
+	}
+	var words []string
+	for _, q := range covers[0] {
+		words = append(words, q.word)
+	}
+	slices.Sort(words)
+	if got, want := strings.Join(words, ","), "butterfly,buttery,caterpillar,hamburger,pentagons,sophister"; got != want {
+		t.Errorf("Expected cover %s, got %s", want, got)
+	}
+}
+
+func TestPrintSolutions(t *testing.T) {
+	trie := partitionTrie()
+	solutions := findSolutions(partitionBoard, findQuartiles(trie, partitionBoard), 0)
+
+	var buf bytes.Buffer
+	printSolutions(&buf, partitionBoard, solutions, nil)
+	if !strings.Contains(buf.String(), "Solution:\n") || !strings.Contains(buf.String(), "  caterpillar (ca|ter|pil|lar) 8\n") {
+		t.Errorf("Expected each quartile with its tiles, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	printSolutions(&buf, partitionBoard, append(solutions, solutions[0]), nil)
+	if !strings.Contains(buf.String(), "Solution 2 of 2:") {
+		t.Errorf("Expected numbered solutions, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	printSolutions(&buf, partitionBoard, nil, nil)
+	if !strings.Contains(buf.String(), "no partition") {
+		t.Errorf("Expected a no-partition message, got %q", buf.String())
+	}
+}
+
+func TestPrintSolutions_CustomScores(t *testing.T) {
+	trie := partitionTrie()
+	solutions := findSolutions(partitionBoard, findQuartiles(trie, partitionBoard), 0)
+	scores, err := parseScoreTable("4=10")
+	if err != nil {
+		t.Fatalf("parseScoreTable() error = %v", err)
+	}
+
+	for _, r := range solutionResults(partitionBoard, solutions[0], scores) {
+		if r.Score != 10 {
+			t.Errorf("Expected %s to score 10 under --scores 4=10, got %d", r.Word, r.Score)
+		}
+	}
+
+	var buf bytes.Buffer
+	printSolutions(&buf, partitionBoard, solutions, scores)
+	if !strings.Contains(buf.String(), "  caterpillar (ca|ter|pil|lar) 10\n") {
+		t.Errorf("Expected the custom points for each quartile, got:\n%s", buf.String())
+	}
+}
+
+func TestFindSolutions_DeterministicOrder(t *testing.T) {
+	tiles := []string{"ab", "cd", "ef", "gh", "ij", "kl", "mn", "op"}
+	trie := NewTrieNode()
+	for _, word := range []string{"abcdijkl", "efghmnop", "abefijmn", "cdghklop"} {
+		trie.Insert(word)
+	}
+	quartiles := findQuartiles(trie, tiles)
+	first, ok := findSolution(tiles, quartiles)
+	if !ok {
+		t.Fatal("Expected a partition")
+	}
+	want := solutionKey(tiles, first)
+
+	// The same first partition comes back however the quartiles are ordered
+	rng := rand.New(rand.NewSource(1))
+	for run := 0; run < 20; run++ {
+		shuffled := slices.Clone(quartiles)
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		if got, _ := findSolution(tiles, shuffled); solutionKey(tiles, got) != want {
+			t.Fatalf("Run %d: first partition %s, expected %s", run, solutionKey(tiles, got), want)
+		}
+	}
+	if want != "abcdijkl:ab|cd|ij|kl efghmnop:ef|gh|mn|op" {
+		t.Errorf("Expected the partition using tile 0's lowest-index quartile first, got %s", want)
+	}
+}
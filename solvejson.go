@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// solveJSON solves a puzzle given as a JSON array of tiles against a
+// dictionary held in memory, either WordNet Prolog facts, a trie file from
+// build-cache, or a newline-delimited wordlist, and returns the results as the
+// JSON array --format json prints. It needs no files, so it backs the
+// browser build, where the page fetches the dictionary and passes its bytes.
+func solveJSON(ctx context.Context, tilesJSON string, dictionary []byte) (string, error) {
+	var lines []string
+	if err := json.Unmarshal([]byte(tilesJSON), &lines); err != nil {
+		return "", fmt.Errorf("parsing tiles: %w", err)
+	}
+
+	var tiles []string
+	for i, line := range lines {
+		tile, err := parseTile(line, i+1, false, io.Discard)
+		if err != nil {
+			return "", err
+		}
+		if tile != "" {
+			tiles = append(tiles, tile)
+		}
+	}
+	if len(tiles) == 0 {
+		return "", ErrEmptyPuzzle
+	}
+
+	opts := options{maxCandidates: defaultMaxCandidates}
+	if err := checkCandidateLimit(len(tiles), opts.tileLimit(), opts.maxCandidates); err != nil {
+		return "", err
+	}
+
+	format, err := sniffDictionaryFormat(bytes.NewReader(dictionary))
+	if err != nil {
+		return "", err
+	}
+	var trie wordTrie
+	switch format {
+	case formatWordNet:
+		nodes := NewTrieNode()
+		_, err = loadDictionaryReader(ctx, bytes.NewReader(dictionary), nodes, false)
+		trie = nodes
+	case formatTrie:
+		// newTrieFile validates the whole layout, so a damaged upload is an
+		// error rather than a panic in the page's callback
+		trie, err = newTrieFile(dictionary)
+	default:
+		nodes := NewTrieNode()
+		_, err = readPlainWordlist(ctx, bytes.NewReader(dictionary), nodes, loadOptions{})
+		trie = nodes
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading dictionary: %w", err)
+	}
+
+	results, _, _, err := solveTiles(ctx, trie, tiles, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := (jsonPrinter{}).PrintResults(&buf, results); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSolveJSON(t *testing.T) {
+	dictionary := []byte("cat\ncats\nat\nAt\n")
+
+	out, err := solveJSON(context.Background(), `["C", "at", "s"]`, dictionary)
+	if err != nil {
+		t.Fatalf("solveJSON() error = %v", err)
+	}
+	var results []Result
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", out, err)
+	}
+	var words []string
+	for _, r := range results {
+		words = append(words, r.Word)
+	}
+	if got := strings.Join(words, ","); got != "at,cat,cats" {
+		t.Errorf("Expected at,cat,cats, got %s", got)
+	}
+
+	// WordNet facts are detected and get their generated forms
+	out, err = solveJSON(context.Background(), `["c", "at", "s"]`, []byte("s(100000001,1,'cat',n,1,3).\n"))
+	if err != nil || !strings.Contains(out, `"word": "cats"`) {
+		t.Errorf("Expected cats from a WordNet dictionary, got %q, %v", out, err)
+	}
+
+	if _, err := solveJSON(context.Background(), `["c", "a7"]`, dictionary); !errors.Is(err, ErrInvalidTile) {
+		t.Errorf("Expected ErrInvalidTile, got %v", err)
+	}
+	if _, err := solveJSON(context.Background(), `[]`, dictionary); !errors.Is(err, ErrEmptyPuzzle) {
+		t.Errorf("Expected ErrEmptyPuzzle, got %v", err)
+	}
+	if _, err := solveJSON(context.Background(), `"cat"`, dictionary); err == nil {
+		t.Error("Expected an error for tiles that are not a JSON array")
+	}
+}
+
+func TestSolveJSON_EmptyTilesDropped(t *testing.T) {
+	dictionary := []byte("cat\n")
+
+	with, err := solveJSON(context.Background(), `["c", "", "  ", "at"]`, dictionary)
+	if err != nil {
+		t.Fatalf("solveJSON() error = %v", err)
+	}
+	without, err := solveJSON(context.Background(), `["c", "at"]`, dictionary)
+	if err != nil {
+		t.Fatalf("solveJSON() error = %v", err)
+	}
+	if with != without {
+		t.Errorf("Expected empty tiles to be dropped, got %s, expected %s", with, without)
+	}
+}
+
+func TestSolveJSON_DamagedTrieFile(t *testing.T) {
+	data := buildTrieFileBytes(t, "cat", "cats", "at")
+	if out, err := solveJSON(context.Background(), `["c", "at", "s"]`, data); err != nil || !strings.Contains(out, `"word": "cats"`) {
+		t.Fatalf("Expected cats from an intact trie file, got %q, %v", out, err)
+	}
+
+	for size := len(trieFileMagic); size < len(data); size++ {
+		if _, err := solveJSON(context.Background(), `["c", "at", "s"]`, data[:size]); !errors.Is(err, ErrBadTrieFile) {
+			t.Errorf("Expected ErrBadTrieFile for an upload truncated to %d bytes, got %v", size, err)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// quartileMaxTiles is the most tiles a single Quartile word may use.
+const quartileMaxTiles = 4
+
+// defaultMaxCandidates is the default cap on projected tile arrangements. A
+// standard 20-tile Quartile board projects 123,520.
+const defaultMaxCandidates = 10_000_000
+
+// projectCandidates returns how many tile arrangements a search of 1 to
+// maxTiles tiles drawn from tileCount tiles could generate, the sum of
+// nPr for r = 1..maxTiles, without generating any of them. The result
+// saturates at math.MaxInt instead of overflowing.
+func projectCandidates(tileCount, maxTiles int) int {
+	total, arrangements := 0, 1
+	for r := 1; r <= maxTiles && r <= tileCount; r++ {
+		factor := tileCount - r + 1
+		if arrangements > math.MaxInt/factor {
+			return math.MaxInt
+		}
+		arrangements *= factor
+		if total > math.MaxInt-arrangements {
+			return math.MaxInt
+		}
+		total += arrangements
+	}
+	return total
+}
+
+// checkCandidateLimit returns ErrTooManyCandidates if the projected number of
+// arrangements exceeds limit. A limit of 0 or less disables the check.
+func checkCandidateLimit(tileCount, maxTiles, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	if projected := projectCandidates(tileCount, maxTiles); projected > limit {
+		return fmt.Errorf("%w: %d tiles with up to %d per word project %d candidates, over the limit of %d (raise --max-candidates)",
+			ErrTooManyCandidates, tileCount, maxTiles, projected, limit)
+	}
+	return nil
+}
+
+// Result is a dictionary word found by joining puzzle tiles in order.
+type Result struct {
+	// Word is the dictionary word the tiles spell.
+	Word string `json:"word"`
+	// Tiles are the puzzle tiles that build Word, in order.
+	Tiles []string `json:"tiles"`
+	// Score is the points the word earns under the active scoreTable.
+	Score int `json:"score"`
+	// Frequency is the word's usage count from the --frequency list, or 0
+	// when no list is loaded or the word is not in it.
+	Frequency int `json:"frequency,omitempty"`
+	// Stem is the dictionary word a --stem match inflects, found by
+	// stripping a suffix from Word, or empty when Word itself is in the
+	// dictionary.
+	Stem string `json:"stem,omitempty"`
+	// Near is the dictionary word an --allow-partial-last-tile match nearly
+	// spells, differing from Word by one edit within its last tile, or
+	// empty for real matches.
+	Near string `json:"near,omitempty"`
+}
+
+// scoreWord returns the Quartile points for a word built from tileCount tiles.
+func scoreWord(tileCount int) int {
+	return quartileScores.scoreWord(tileCount)
+}
+
+// solveTiles finds every word formed from the tiles under the search
+// settings in opts: the per-word tile limit, --tiles, --order,
+// --tile-frequency-weighted, --contains, --stem, --allow-partial-last-tile, the prefix cache, the
+// timeout, and progress reporting. It does no printing. The --stem and
+// --allow-partial-last-tile matches, which are not dictionary words, come
+// back unscored in loose rather than among the results. On timeout, or when
+// ctx is cancelled, it returns the results found so far along with the
+// context's error.
+func solveTiles(ctx context.Context, trie wordTrie, tiles []string, opts options) (results, loose []Result, stats Stats, err error) {
+	tiles = dropEmptyTiles(tiles)
+
+	maxTiles := opts.tileLimit()
+	if opts.exactTiles > 0 {
+		maxTiles = opts.exactTiles
+	}
+
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	// Stopping after the first words found needs one worker exploring
+	// high-yield tiles first, so the same words are found every run
+	workers := runtime.GOMAXPROCS(0)
+	stopAfter := opts.stopAfter()
+	if stopAfter > 0 {
+		tiles = orderTilesByYield(trie, tiles)
+		workers = 1
+	}
+
+	progress := newProgressReporter(opts.progress, projectCandidates(len(tiles), maxTiles))
+	results, loose, stats, err = findMatchesWorkers(ctx, trie, tiles, searchSettings{
+		maxTiles:        maxTiles,
+		workers:         workers,
+		stopAfter:       stopAfter,
+		cachePrefixes:   opts.prefixCache,
+		stem:            opts.stem,
+		partialLastTile: opts.partialLastTile,
+		debug:           opts.debug,
+	}, progress)
+	if opts.exactTiles > 0 {
+		results = filterTileCount(results, opts.exactTiles)
+		loose = filterTileCount(loose, opts.exactTiles)
+		stats.Matches = len(results)
+	}
+	if opts.contains != "" {
+		results = filterContains(results, opts.contains)
+		loose = filterContains(loose, opts.contains)
+		stats.Matches = len(results)
+	}
+	if opts.scores != nil {
+		for i := range results {
+			results[i].Score = opts.scores.scoreWord(len(results[i].Tiles))
+		}
+	}
+	if opts.frequencies != nil {
+		for i := range results {
+			results[i].Frequency = opts.frequencies[results[i].Word]
+		}
+	}
+	switch opts.order {
+	case orderRarity:
+		sortMatchesByRarity(results)
+	case orderFrequency:
+		sortMatchesByFrequency(results, opts.frequencies)
+	}
+	return results, loose, stats, err
+}
+
+// orderTilesByYield returns a copy of tiles sorted so that tiles beginning the
+// most dictionary words come first. Tiles with equal counts keep puzzle order.
+// The search takes first tiles in slice order, so arrangements led by
+// high-yield tiles are then explored before those led by low-yield tiles.
+func orderTilesByYield(trie wordTrie, tiles []string) []string {
+	counts := make(map[string]int, len(tiles))
+	for _, tile := range tiles {
+		counts[tile] = trie.CountPrefix(tile)
+	}
+
+	ordered := append([]string{}, tiles...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return counts[ordered[i]] > counts[ordered[j]]
+	})
+	return ordered
+}
+
+// dropEmptyTiles returns tiles without any empty strings. The readers
+// already skip blank lines, but an empty tile reaching the search would be
+// placed between real tiles as a no-op, repeating every word once per
+// position it could fill and inflating the arrangement count.
+func dropEmptyTiles(tiles []string) []string {
+	if !slices.Contains(tiles, "") {
+		return tiles
+	}
+	kept := make([]string, 0, len(tiles))
+	for _, tile := range tiles {
+		if tile != "" {
+			kept = append(kept, tile)
+		}
+	}
+	return kept
+}
+
+// sortMatches puts matches in the solver's documented output order: fewest
+// tiles first, then alphabetically by word, then by tile sequence for words
+// that can be built more than one way. The order depends only on the set of
+// matches, never on tile order or search order, so output is reproducible.
+func sortMatches(matches []Result) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if len(a.Tiles) != len(b.Tiles) {
+			return len(a.Tiles) < len(b.Tiles)
+		}
+		if a.Word != b.Word {
+			return a.Word < b.Word
+		}
+		return strings.Join(a.Tiles, "|") < strings.Join(b.Tiles, "|")
+	})
+}
+
+// filterTileCount returns only the matches built from exactly tileCount tiles.
+func filterTileCount(matches []Result, tileCount int) []Result {
+	var filtered []Result
+	for _, m := range matches {
+		if len(m.Tiles) == tileCount {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+// filterContains returns only the matches whose word contains substr,
+// ignoring its case.
+func filterContains(matches []Result, substr string) []Result {
+	substr = normalizeWord(substr)
+	var filtered []Result
+	for _, m := range matches {
+		if strings.Contains(m.Word, substr) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+// totalScore sums the Quartile points of every result.
+func totalScore(matches []Result) int {
+	total := 0
+	for _, m := range matches {
+		total += m.Score
+	}
+	return total
+}
@@ -0,0 +1,749 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOrderTilesByYield(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"stone", "stop", "story", "strap", "quiz"} {
+		trie.Insert(word)
+	}
+
+	tiles := []string{"qu", "zz", "st"}
+	ordered := orderTilesByYield(trie, tiles)
+
+	expected := []string{"st", "qu", "zz"}
+	if strings.Join(ordered, ",") != strings.Join(expected, ",") {
+		t.Errorf("orderTilesByYield() = %v, expected %v", ordered, expected)
+	}
+	if tiles[0] != "qu" {
+		t.Error("Expected orderTilesByYield to leave the input slice untouched")
+	}
+}
+
+// TestSolveTiles_TileFrequencyWeighted checks that with --limit the
+// weighted search explores a high-yield first tile before a low-yield one
+// and stops once it has enough words.
+func TestSolveTiles_TileFrequencyWeighted(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"stone", "stop", "story", "strap", "quiz"} {
+		trie.Insert(word)
+	}
+	// qu comes first in the puzzle, but st begins four words to its one
+	tiles := []string{"qu", "iz", "st", "op"}
+
+	results, _, stats, err := solveTiles(context.Background(), trie, tiles, options{tileWeighted: true, limit: 1})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Word != "stop" {
+		t.Errorf("Expected the st tile explored first to find stop, got %+v", results)
+	}
+
+	_, _, all, err := solveTiles(context.Background(), trie, tiles, options{limit: 1})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	if all.Matches != 2 {
+		t.Errorf("Expected both words without --tile-frequency-weighted, got %d", all.Matches)
+	}
+	if stats.Candidates >= all.Candidates {
+		t.Errorf("Expected the weighted search to stop early, checked %d arrangements of %d", stats.Candidates, all.Candidates)
+	}
+
+	// A report that needs every word turns stopping early off
+	results, _, _, err = solveTiles(context.Background(), trie, tiles, options{tileWeighted: true, limit: 1, maxScore: true})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected every word when --max-score needs them, got %+v", results)
+	}
+}
+
+func TestSolveTiles_ReturnsResults(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"at", "cat", "cats", "quartile"} {
+		trie.Insert(word)
+	}
+
+	results, _, stats, err := solveTiles(context.Background(), trie, []string{"qu", "ar", "ti", "le", "c", "at", "s"}, options{})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+
+	want := []Result{
+		{Word: "at", Tiles: []string{"at"}, Score: 1},
+		{Word: "cat", Tiles: []string{"c", "at"}, Score: 2},
+		{Word: "cats", Tiles: []string{"c", "at", "s"}, Score: 4},
+		{Word: "quartile", Tiles: []string{"qu", "ar", "ti", "le"}, Score: 8},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("solveTiles() = %+v, expected %+v", results, want)
+	}
+	if stats.Matches != len(want) {
+		t.Errorf("Expected stats.Matches %d, got %d", len(want), stats.Matches)
+	}
+}
+
+func TestFindMatches(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+	trie.Insert("at")
+
+	matches, _, _ := findMatches(context.Background(), trie, []string{"c", "at"}, 4, false, nil)
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+	if matches[0].Word != "at" || matches[1].Word != "cat" {
+		t.Errorf("Expected matches [at cat], got %v", matches)
+	}
+	if strings.Join(matches[1].Tiles, "|") != "c|at" {
+		t.Errorf("Expected 'cat' built from c|at, got %v", matches[1].Tiles)
+	}
+	if totalScore(matches) != 3 {
+		t.Errorf("Expected total score 3, got %d", totalScore(matches))
+	}
+}
+
+func TestScoreWord(t *testing.T) {
+	expected := map[int]int{1: 1, 2: 2, 3: 4, 4: 8, 5: 0}
+	for tileCount, points := range expected {
+		if got := scoreWord(tileCount); got != points {
+			t.Errorf("scoreWord(%d) = %d, expected %d", tileCount, got, points)
+		}
+	}
+}
+
+func TestSolvePuzzle_ExactTiles(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"at", "cat", "cater", "caters"} {
+		trie.Insert(word)
+	}
+	tiles := []string{"c", "at", "er", "s"}
+
+	tests := []struct {
+		exactTiles int
+		expected   []string
+	}{
+		{2, []string{"cat"}},
+		{3, []string{"cater"}},
+		{4, []string{"caters"}},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		matches, stats := solvePuzzle(context.Background(), trie, tiles, options{exactTiles: tt.exactTiles}, &buf)
+
+		var words []string
+		for _, m := range matches {
+			if len(m.Tiles) != tt.exactTiles {
+				t.Errorf("--tiles %d returned %q built from %d tiles", tt.exactTiles, m.Word, len(m.Tiles))
+			}
+			words = append(words, m.Word)
+		}
+		if strings.Join(words, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("--tiles %d returned %v, expected %v", tt.exactTiles, words, tt.expected)
+		}
+		if stats.Matches != len(tt.expected) {
+			t.Errorf("--tiles %d stats.Matches = %d, expected %d", tt.exactTiles, stats.Matches, len(tt.expected))
+		}
+		if lines := strings.Count(buf.String(), "\n"); lines != len(tt.expected) {
+			t.Errorf("--tiles %d printed %d lines, expected %d", tt.exactTiles, lines, len(tt.expected))
+		}
+	}
+}
+
+func TestSolveTiles_Contains(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"straw", "stray", "star", "art", "tray"} {
+		trie.Insert(word)
+	}
+
+	results, _, stats, err := solveTiles(context.Background(), trie, []string{"st", "r", "aw", "ay", "a", "t"}, options{contains: "STR"})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	var words []string
+	for _, r := range results {
+		words = append(words, r.Word)
+	}
+	if got := strings.Join(words, ","); got != "straw,stray" {
+		t.Errorf("Expected only straw,stray to contain str, got %s", got)
+	}
+	if stats.Matches != len(results) {
+		t.Errorf("Expected stats.Matches %d, got %d", len(results), stats.Matches)
+	}
+}
+
+func TestRunWithOptions_InvalidExactTiles(t *testing.T) {
+	var buf bytes.Buffer
+	err := runWithOptions(context.Background(), options{dictionaryPath: "dict.pl", puzzlePaths: []string{"puzzle.txt"}, exactTiles: 5}, &buf)
+	if err == nil || !strings.Contains(err.Error(), "--tiles") {
+		t.Errorf("Expected --tiles validation error, got %v", err)
+	}
+}
+
+func TestFindMatches_Cancelled(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("ab")
+
+	tiles := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	matches, stats, err := findMatches(ctx, trie, tiles, 4, false, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(matches) != 0 || stats.Candidates != 0 {
+		t.Errorf("Expected no work after cancellation, got %d matches and %d candidates", len(matches), stats.Candidates)
+	}
+}
+
+func TestSolvePuzzle_Timeout(t *testing.T) {
+	trie := NewTrieNode()
+	tiles := make([]string, 20)
+	for i := range tiles {
+		tiles[i] = string(rune('a' + i))
+	}
+	// Every one- and two-tile arrangement is a valid prefix
+	for _, a := range tiles {
+		for _, b := range tiles {
+			trie.Insert(a + b + "zz")
+		}
+	}
+
+	var buf, notes bytes.Buffer
+	matches, stats := solvePuzzle(context.Background(), trie, tiles, options{timeout: time.Nanosecond, diagnostics: &notes}, &buf)
+
+	if !strings.Contains(notes.String(), "results are partial") {
+		t.Errorf("Expected partial-results notice, got %q", notes.String())
+	}
+	if len(matches) != 0 {
+		t.Errorf("Expected no matches, got %d", len(matches))
+	}
+	if full := 20 + 20*19; stats.Candidates >= full {
+		t.Errorf("Expected early termination, checked %d candidates", stats.Candidates)
+	}
+}
+
+func TestSolvePuzzle_MaxTiles(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"a", "ab", "abc", "abcd"} {
+		trie.Insert(word)
+	}
+	tiles := []string{"a", "b", "c", "d"}
+
+	tests := []struct {
+		maxTiles   int
+		candidates int
+		longest    string
+	}{
+		// 4 single tiles, then pairs extending the only live prefix "a"
+		{2, 7, "ab"},
+		// plus the 3-tile extensions of the only live pair "ab"
+		{3, 9, "abc"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		matches, stats := solvePuzzle(context.Background(), trie, tiles, options{maxTiles: tt.maxTiles}, &buf)
+
+		for _, m := range matches {
+			if len(m.Tiles) > tt.maxTiles {
+				t.Errorf("--max-tiles %d returned %q built from %d tiles", tt.maxTiles, m.Word, len(m.Tiles))
+			}
+		}
+		if last := matches[len(matches)-1].Word; last != tt.longest {
+			t.Errorf("--max-tiles %d longest word = %q, expected %q", tt.maxTiles, last, tt.longest)
+		}
+		if stats.Candidates != tt.candidates {
+			t.Errorf("--max-tiles %d checked %d candidates, expected %d", tt.maxTiles, stats.Candidates, tt.candidates)
+		}
+	}
+}
+
+func TestRunWithOptions_MaxTilesValidation(t *testing.T) {
+	var buf bytes.Buffer
+	err := runWithOptions(context.Background(), options{dictionaryPath: "dict.pl", puzzlePaths: []string{"puzzle.txt"}, maxTiles: 2, exactTiles: 3}, &buf)
+	if err == nil || !strings.Contains(err.Error(), "--tiles must be between 1 and 2") {
+		t.Errorf("Expected --tiles to be bounded by --max-tiles, got %v", err)
+	}
+
+	err = runWithOptions(context.Background(), options{dictionaryPath: "dict.pl", puzzlePaths: []string{"puzzle.txt"}, maxTiles: -1}, &buf)
+	if err == nil || !strings.Contains(err.Error(), "--max-tiles") {
+		t.Errorf("Expected --max-tiles validation error, got %v", err)
+	}
+
+	buf.Reset()
+	_ = runWithOptions(context.Background(), options{dictionaryPath: "/nonexistent/dict.pl", puzzlePaths: []string{"puzzle.txt"}, maxTiles: 8, diagnostics: &buf}, &buf)
+	if !strings.Contains(buf.String(), "Warning: --max-tiles 8") {
+		t.Errorf("Expected a warning for a large --max-tiles, got %q", buf.String())
+	}
+}
+
+func TestSortMatches(t *testing.T) {
+	matches := []Result{
+		{Word: "cat", Tiles: []string{"c", "at"}},
+		{Word: "at", Tiles: []string{"at"}},
+		{Word: "ab", Tiles: []string{"ab"}},
+		{Word: "ab", Tiles: []string{"a", "b"}},
+		{Word: "act", Tiles: []string{"ac", "t"}},
+	}
+	sortMatches(matches)
+
+	var got []string
+	for _, m := range matches {
+		got = append(got, strings.Join(m.Tiles, "|"))
+	}
+	expected := "ab,at,a|b,ac|t,c|at"
+	if strings.Join(got, ",") != expected {
+		t.Errorf("sortMatches() order = %v, expected %s", got, expected)
+	}
+}
+
+func TestRunWithOptions_DeterministicOutput(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", `s(100000001,1,'cat',n,1,3).
+s(100000002,1,'act',v,1,3).
+s(100000003,1,'tac',n,1,3).
+s(100000004,1,'at',n,1,2).`)
+	puzzlePath := writeTempFile(t, "puzzle.txt", "t\nc\na\nat\n")
+	shuffledPath := writeTempFile(t, "shuffled.txt", "at\na\nc\nt\n")
+
+	solve := func(puzzle string) string {
+		var buf bytes.Buffer
+		// Allow the single-letter tiles so no per-tile warnings, which
+		// follow puzzle order, are mixed into the results
+		opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzle}, minTileLength: 1}
+		if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+			t.Fatalf("runWithOptions() unexpected error: %v", err)
+		}
+		return buf.String()
+	}
+
+	first, second := solve(puzzlePath), solve(puzzlePath)
+	if first != second {
+		t.Errorf("Expected identical output across runs:\n%s\n---\n%s", first, second)
+	}
+
+	// Tile order must not change the results either
+	shuffled := solve(shuffledPath)
+	if first != shuffled {
+		t.Errorf("Expected output independent of tile order:\n%s\n---\n%s", first, shuffled)
+	}
+}
+
+func TestProjectCandidates(t *testing.T) {
+	tiles := []string{"a", "b", "c", "d", "e"}
+	for maxTiles := 0; maxTiles <= 6; maxTiles++ {
+		projected := projectCandidates(len(tiles), maxTiles)
+		actual := len(generatePermutations(tiles, maxTiles))
+		if projected != actual {
+			t.Errorf("projectCandidates(5, %d) = %d, generated %d", maxTiles, projected, actual)
+		}
+	}
+
+	if got := projectCandidates(20, 4); got != 123520 {
+		t.Errorf("projectCandidates(20, 4) = %d, expected 123520", got)
+	}
+	if got := projectCandidates(1000, 1000); got != math.MaxInt {
+		t.Errorf("Expected projection to saturate, got %d", got)
+	}
+}
+
+func TestCheckCandidateLimit(t *testing.T) {
+	if err := checkCandidateLimit(20, 4, defaultMaxCandidates); err != nil {
+		t.Errorf("Expected a standard board to fit the default limit, got %v", err)
+	}
+	if err := checkCandidateLimit(20, 8, 0); err != nil {
+		t.Errorf("Expected a zero limit to disable the check, got %v", err)
+	}
+
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\ns\n")
+	var buf bytes.Buffer
+	err := runWithOptions(context.Background(), options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, maxCandidates: 10}, &buf)
+	if !errors.Is(err, ErrTooManyCandidates) {
+		t.Fatalf("Expected ErrTooManyCandidates, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "project 15 candidates") {
+		t.Errorf("Expected the projection in the error, got %v", err)
+	}
+	if strings.Contains(buf.String(), "cat") {
+		t.Error("Expected no solving once the cap is exceeded")
+	}
+}
+
+// loadBenchDictionary loads the benchmark dictionary fixture, a few hundred
+// common English words plus the quartiles of the sample puzzles.
+func loadBenchDictionary(tb testing.TB) *TrieNode {
+	tb.Helper()
+	trie := NewTrieNode()
+	if _, err := loadPlainWordlist(context.Background(), "testdata/bench_words.txt", trie, loadOptions{}); err != nil {
+		tb.Fatal(err)
+	}
+	return trie
+}
+
+// bruteForceWords finds words by generating every tile arrangement up front
+// and looking each one up, the approach the pruned search replaced.
+func bruteForceWords(trie *TrieNode, tiles []string) []string {
+	words := checkInTrie(trie, generatePermutations(tiles, quartileMaxTiles), false)
+	slices.Sort(words)
+	return slices.Compact(words)
+}
+
+// prunedWords finds words with the prefix-pruned search.
+func prunedWords(trie *TrieNode, tiles []string) []string {
+	results, _, _ := findMatches(context.Background(), trie, tiles, quartileMaxTiles, false, nil)
+	var words []string
+	for _, r := range results {
+		words = append(words, r.Word)
+	}
+	slices.Sort(words)
+	return slices.Compact(words)
+}
+
+// checkSolversAgree fails unless both searches find the same, non-empty set
+// of words for a sample puzzle, and returns its tiles.
+func checkSolversAgree(tb testing.TB, trie *TrieNode, puzzlePath string) []string {
+	tb.Helper()
+	tiles, err := readPuzzle(puzzlePath, false, io.Discard)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	brute, pruned := bruteForceWords(trie, tiles), prunedWords(trie, tiles)
+	if len(pruned) == 0 {
+		tb.Fatalf("%s: expected the fixture dictionary to yield words", puzzlePath)
+	}
+	if !slices.Equal(brute, pruned) {
+		tb.Fatalf("%s: brute force found %v, pruned search found %v", puzzlePath, brute, pruned)
+	}
+	return tiles
+}
+
+func TestPrunedSearch_MatchesBruteForce(t *testing.T) {
+	trie := loadBenchDictionary(t)
+	paths, err := filepath.Glob("samples/*.txt")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("Expected sample puzzles, got %v, %v", paths, err)
+	}
+	for _, path := range paths {
+		checkSolversAgree(t, trie, path)
+	}
+}
+
+func BenchmarkSolveBruteForce(b *testing.B) {
+	trie := loadBenchDictionary(b)
+	tiles := checkSolversAgree(b, trie, "samples/puzzle1.txt")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checkInTrie(trie, generatePermutations(tiles, quartileMaxTiles), false)
+	}
+}
+
+func BenchmarkSolvePruned(b *testing.B) {
+	trie := loadBenchDictionary(b)
+	tiles := checkSolversAgree(b, trie, "samples/puzzle1.txt")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := findMatches(context.Background(), trie, tiles, quartileMaxTiles, false, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// combinedSampleTiles returns the tiles of every sample puzzle on one board,
+// a search far larger than any single puzzle.
+func combinedSampleTiles(tb testing.TB) []string {
+	tb.Helper()
+	paths, err := filepath.Glob("samples/*.txt")
+	if err != nil || len(paths) == 0 {
+		tb.Fatalf("Expected sample puzzles, got %v, %v", paths, err)
+	}
+	var tiles []string
+	for _, path := range paths {
+		puzzle, err := readPuzzle(path, false, io.Discard)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		tiles = append(tiles, puzzle...)
+	}
+	return tiles
+}
+
+// TestFindMatchesWorkers_SameAsSerial runs under the race detector in CI
+// (go test -race), which checks that workers share only the read-only trie.
+func TestFindMatchesWorkers_SameAsSerial(t *testing.T) {
+	trie := loadBenchDictionary(t)
+	tiles := combinedSampleTiles(t)
+
+	serial, _, serialStats, err := findMatchesWorkers(context.Background(), trie, tiles, searchSettings{maxTiles: quartileMaxTiles, workers: 1}, nil)
+	if err != nil {
+		t.Fatalf("findMatchesWorkers(1) error = %v", err)
+	}
+	if len(serial) == 0 {
+		t.Fatal("Expected the combined board to yield words")
+	}
+
+	for _, workers := range []int{2, 8, len(tiles) + 1} {
+		var buf bytes.Buffer
+		progress := newProgressReporter(&buf, projectCandidates(len(tiles), quartileMaxTiles))
+		parallel, _, stats, err := findMatchesWorkers(context.Background(), trie, tiles, searchSettings{maxTiles: quartileMaxTiles, workers: workers}, progress)
+		if err != nil {
+			t.Fatalf("findMatchesWorkers(%d) error = %v", workers, err)
+		}
+		if !reflect.DeepEqual(parallel, serial) {
+			t.Errorf("findMatchesWorkers(%d) found %d matches in a different order or set than the serial %d", workers, len(parallel), len(serial))
+		}
+		if stats.Candidates != serialStats.Candidates || stats.Pruned != serialStats.Pruned {
+			t.Errorf("findMatchesWorkers(%d) stats = %+v, expected %+v", workers, stats, serialStats)
+		}
+	}
+}
+
+func TestFindMatches_NoEmptyWord(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("")
+	trie.Insert("cat")
+	if trie.CountPrefix("") != 1 {
+		t.Errorf("Expected only cat to be counted, got %d words", trie.CountPrefix(""))
+	}
+
+	// An empty candidate, here from an empty tile, must never be a match
+	matches, _, err := findMatches(context.Background(), trie, []string{"", "c", "at"}, quartileMaxTiles, false, nil)
+	if err != nil {
+		t.Fatalf("findMatches() error = %v", err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("Expected cat to be found")
+	}
+	for _, m := range matches {
+		if m.Word == "" {
+			t.Errorf("Expected no empty word, got a match built from %q", m.Tiles)
+		}
+	}
+}
+
+func TestSolveTiles_DropsEmptyTiles(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+
+	results, _, stats, err := solveTiles(context.Background(), trie, []string{"c", "", "at", ""}, options{})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	if len(results) != 1 || strings.Join(results[0].Tiles, "|") != "c|at" {
+		t.Errorf("Expected only c|at, got %v", results)
+	}
+	if _, _, want, _ := solveTiles(context.Background(), trie, []string{"c", "at"}, options{}); stats.Candidates != want.Candidates {
+		t.Errorf("Expected %d candidates as without empty tiles, got %d", want.Candidates, stats.Candidates)
+	}
+}
+
+// BenchmarkSolveParallel compares one worker with one per CPU on the
+// combined sample board; the speedup grows with GOMAXPROCS.
+func BenchmarkSolveParallel(b *testing.B) {
+	trie := loadBenchDictionary(b)
+	tiles := combinedSampleTiles(b)
+
+	counts := []int{1}
+	if cpus := runtime.GOMAXPROCS(0); cpus > 1 {
+		counts = append(counts, cpus)
+	}
+	for _, workers := range counts {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, _, err := findMatchesWorkers(context.Background(), trie, tiles, searchSettings{maxTiles: quartileMaxTiles, workers: workers}, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFindMatchesWorkers_PrefixCache(t *testing.T) {
+	trie := loadBenchDictionary(t)
+	tiles := combinedSampleTiles(t)
+	buf := withLogger(t, slog.LevelDebug)
+
+	uncached, _, uncachedStats, err := findMatchesWorkers(context.Background(), trie, tiles, searchSettings{maxTiles: quartileMaxTiles, workers: 1}, nil)
+	if err != nil {
+		t.Fatalf("findMatchesWorkers() without cache error = %v", err)
+	}
+	if strings.Contains(buf.String(), "prefix cache") {
+		t.Errorf("Expected no cache report with the cache off, got:\n%s", buf.String())
+	}
+
+	cached, _, cachedStats, err := findMatchesWorkers(context.Background(), trie, tiles, searchSettings{maxTiles: quartileMaxTiles, workers: 1, cachePrefixes: true, debug: true}, nil)
+	if err != nil {
+		t.Fatalf("findMatchesWorkers() with cache error = %v", err)
+	}
+	if !reflect.DeepEqual(cached, uncached) {
+		t.Errorf("Expected the cache to leave results unchanged, got %d matches, expected %d", len(cached), len(uncached))
+	}
+	if cachedStats.Candidates != uncachedStats.Candidates || cachedStats.Pruned != uncachedStats.Pruned {
+		t.Errorf("Expected the cache to leave stats unchanged, got %+v, expected %+v", cachedStats, uncachedStats)
+	}
+	if !strings.Contains(buf.String(), "prefix cache") || !strings.Contains(buf.String(), "hit_rate=") {
+		t.Errorf("Expected a debug line with the cache hit rate, got:\n%s", buf.String())
+	}
+}
+
+func TestSolveTiles_AllowPartialLastTile(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"castle", "cast"} {
+		trie.Insert(word)
+	}
+	// "lx" is a mistyped "le"
+	tiles := []string{"ca", "st", "lx"}
+
+	_, loose, _, err := solveTiles(context.Background(), trie, tiles, options{})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	if len(loose) != 0 {
+		t.Errorf("Expected no near misses by default, got %+v", loose)
+	}
+
+	results, loose, stats, err := solveTiles(context.Background(), trie, tiles, options{partialLastTile: true})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	want := []Result{{Word: "castlx", Tiles: []string{"ca", "st", "lx"}, Near: "castle"}}
+	if !reflect.DeepEqual(loose, want) {
+		t.Errorf("Expected near misses %+v, got %+v", want, loose)
+	}
+	for _, r := range results {
+		if r.Near != "" {
+			t.Errorf("Expected near misses kept apart from the words, got %+v", r)
+		}
+	}
+	if stats.Matches != len(results) {
+		t.Errorf("Expected stats.Matches = %d words, got %d", len(results), stats.Matches)
+	}
+}
+
+// TestSolveBoard_NearMissesNotCounted checks that a near miss is printed
+// but neither scored nor counted as a found word.
+func TestSolveBoard_NearMissesNotCounted(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+
+	var buf bytes.Buffer
+	found, err := solveBoard(context.Background(), trie, []string{"c", "ax"}, loadSummary{}, options{partialLastTile: true, maxScore: true}, &buf)
+	if err != nil {
+		t.Fatalf("solveBoard() error = %v", err)
+	}
+	if found != 0 {
+		t.Errorf("Expected 0 words found, got %d", found)
+	}
+	if !strings.Contains(buf.String(), "cax") || !strings.Contains(buf.String(), "near cat") {
+		t.Errorf("Expected the near miss cax to be printed, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Max score: 0 points") {
+		t.Errorf("Expected the near miss to earn no points, got:\n%s", buf.String())
+	}
+}
+
+// TestFindMatches_LetterAvailability checks that an arrangement is never
+// built when no unused tile begins with a letter continuing its prefix.
+func TestFindMatches_LetterAvailability(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cats")
+	tiles := []string{"c", "at", "x", "y"}
+
+	// cat continues only with s, which no unused tile starts with, so
+	// c|at|x and c|at|y, and their four-tile extensions, are skipped
+	matches, stats, err := findMatches(context.Background(), trie, tiles, 4, false, nil)
+	if err != nil {
+		t.Fatalf("findMatches() error = %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Expected no words, got %+v", matches)
+	}
+	// c, c|at, c|x, c|y, at, x, y
+	if stats.Candidates != 7 {
+		t.Errorf("Expected 7 candidates, got %d", stats.Candidates)
+	}
+
+	// --stem matches need not continue the trie, so it keeps them
+	_, _, stemStats, err := findMatchesWorkers(context.Background(), trie, tiles, searchSettings{maxTiles: 4, workers: 1, stem: true}, nil)
+	if err != nil {
+		t.Fatalf("findMatchesWorkers() error = %v", err)
+	}
+	if stemStats.Candidates <= stats.Candidates {
+		t.Errorf("Expected --stem to check c|at|x and c|at|y, got %d candidates", stemStats.Candidates)
+	}
+}
+
+// TestFindMatches_MatchesGenerateAndCheck compares the depth-first search
+// with the generate-and-check search it replaced, which built every
+// arrangement of up to four tiles and kept those spelling a word. Pruning
+// only skips arrangements whose letters begin no word, so both must find
+// the same words from the same tile sequences.
+func TestFindMatches_MatchesGenerateAndCheck(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"at", "ate", "cat", "cats", "scat", "castle", "castles", "eat", "eats", "sea", "seat", "tea", "teas", "least", "steal", "tale", "tales", "stale"} {
+		trie.Insert(word)
+	}
+	tiles := []string{"c", "at", "s", "tle", "e", "a", "st", "le", "ea"}
+
+	want := make(map[string]int)
+	for size := 1; size <= 4; size++ {
+		for _, combo := range combinations(tiles, size) {
+			for _, perm := range permutations(combo) {
+				if word := strings.Join(perm, ""); trie.Search(word) {
+					want[word+" "+strings.Join(perm, "|")]++
+				}
+			}
+		}
+	}
+	if len(want) == 0 {
+		t.Fatal("Expected the board to spell words")
+	}
+
+	matches, _, err := findMatches(context.Background(), trie, tiles, 4, false, nil)
+	if err != nil {
+		t.Fatalf("findMatches() error = %v", err)
+	}
+	got := make(map[string]int)
+	previous := 0
+	for _, m := range matches {
+		got[m.Word+" "+strings.Join(m.Tiles, "|")]++
+		if len(m.Tiles) < previous {
+			t.Errorf("Expected matches ordered by tile count, got %q after %d tiles", m.Word, previous)
+		}
+		previous = len(m.Tiles)
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("Pruned search found %d arrangements, generate-and-check found %d", len(got), len(want))
+	}
+	for key, n := range want {
+		if got[key] != n {
+			t.Errorf("%s: generate-and-check found it %d times, pruned search %d", key, n, got[key])
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stats captures counters and phase timings for a single solve.
+type Stats struct {
+	// LoadDuration is how long the dictionary took to load, when known.
+	LoadDuration time.Duration
+	// Candidates is the number of tile arrangements checked against the dictionary.
+	Candidates int
+	// Pruned is the number of partial arrangements abandoned because no
+	// dictionary word begins with their letters.
+	Pruned int
+	// Matches is the number of arrangements that spelled dictionary words.
+	Matches int
+	// SolveDuration is how long the search over tile arrangements took.
+	SolveDuration time.Duration
+}
+
+// printStats writes a human-readable summary of the solve statistics.
+func printStats(w io.Writer, stats Stats) {
+	fmt.Fprintln(w, "Stats:")
+	if stats.LoadDuration > 0 {
+		fmt.Fprintf(w, "  Dictionary load: %v\n", stats.LoadDuration)
+	}
+	fmt.Fprintf(w, "  Candidates:      %d\n", stats.Candidates)
+	fmt.Fprintf(w, "  Pruned:          %d\n", stats.Pruned)
+	fmt.Fprintf(w, "  Matches:         %d\n", stats.Matches)
+	fmt.Fprintf(w, "  Solve time:      %v\n", stats.SolveDuration)
+}
+
+// heapInUse returns the bytes of live heap after forcing a collection, so
+// that the difference between two calls approximates retained memory.
+func heapInUse() uint64 {
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return mem.HeapAlloc
+}
+
+// printTrieReport writes the trie's node count, approximate memory footprint,
+// and the distribution of stored words by length.
+func printTrieReport(w io.Writer, trie *TrieNode, heapBytes uint64) {
+	fmt.Fprintf(w, "Trie nodes: %d\n", trie.NodeCount())
+	fmt.Fprintf(w, "Approximate trie memory: %.1f MB\n", float64(heapBytes)/(1024*1024))
+
+	counts := trie.wordLengthCounts()
+	lengths := make([]int, 0, len(counts))
+	for length := range counts {
+		lengths = append(lengths, length)
+	}
+	sort.Ints(lengths)
+
+	parts := make([]string, 0, len(lengths))
+	for _, length := range lengths {
+		parts = append(parts, fmt.Sprintf("%d:%d", length, counts[length]))
+	}
+	fmt.Fprintf(w, "Words by length: %s\n", strings.Join(parts, " "))
+}
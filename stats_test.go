@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindMatches_Stats(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("cat")
+	trie.Insert("at")
+
+	// c, cat, cx, at, x are checked; cx and x start no dictionary word,
+	// and no unused tile's letter continues cat or at, so catx, atc, and
+	// atx are never built.
+	_, stats, _ := findMatches(context.Background(), trie, []string{"c", "at", "x"}, 4, false, nil)
+
+	if stats.Candidates != 5 {
+		t.Errorf("Expected 5 candidates, got %d", stats.Candidates)
+	}
+	if stats.Pruned != 4 {
+		t.Errorf("Expected 4 pruned, got %d", stats.Pruned)
+	}
+	if stats.Matches != 2 {
+		t.Errorf("Expected 2 matches, got %d", stats.Matches)
+	}
+	if stats.SolveDuration <= 0 {
+		t.Error("Expected a positive solve duration")
+	}
+}
+
+func TestPrintStats(t *testing.T) {
+	stats := Stats{
+		LoadDuration:  5 * time.Millisecond,
+		Candidates:    120,
+		Pruned:        30,
+		Matches:       4,
+		SolveDuration: time.Millisecond,
+	}
+
+	var buf bytes.Buffer
+	printStats(&buf, stats)
+	output := buf.String()
+
+	for _, expected := range []string{"Dictionary load: 5ms", "Candidates:      120", "Pruned:          30", "Matches:         4", "Solve time:      1ms"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected stats output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	buf.Reset()
+	printStats(&buf, Stats{})
+	if strings.Contains(buf.String(), "Dictionary load") {
+		t.Error("Expected unknown load time to be omitted")
+	}
+}
+
+func TestRunWithOptions_Stats(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "c\nat\n")
+
+	var buf bytes.Buffer
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, stats: true}
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Dictionary load:") || !strings.Contains(buf.String(), "Matches:         1") {
+		t.Errorf("Expected stats in output, got:\n%s", buf.String())
+	}
+}
+
+func TestPrintTrieReport(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"at", "cat", "cats"} {
+		trie.Insert(word)
+	}
+
+	var buf bytes.Buffer
+	printTrieReport(&buf, trie, 3*1024*1024)
+	output := buf.String()
+
+	for _, expected := range []string{"Trie nodes: 7", "Approximate trie memory: 3.0 MB", "Words by length: 2:1 3:1 4:1"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected report to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import "strings"
+
+// stemSuffixes are the inflectional endings --stem strips, each with the
+// ending that may have been replaced, longest first so "ies" is tried
+// before "s".
+var stemSuffixes = []struct {
+	suffix  string
+	replace string
+}{
+	{"ies", "y"}, // parties → party
+	{"ied", "y"}, // tried → try
+	{"ing", ""},  // jumping → jump
+	{"ing", "e"}, // making → make
+	{"est", ""},  // tallest → tall
+	{"est", "e"}, // widest → wide
+	{"es", ""},   // boxes → box
+	{"ed", ""},   // jumped → jump
+	{"ed", "e"},  // baked → bake
+	{"er", ""},   // taller → tall
+	{"er", "e"},  // wider → wide
+	{"ly", ""},   // quickly → quick
+	{"s", ""},    // cats → cat
+}
+
+// stemBase returns a dictionary word that word inflects by one of
+// stemSuffixes, or "" if none is found. A doubled final consonant left by
+// the suffix is also undone, so "running" finds "run". The base must keep
+// at least two letters.
+func stemBase(trie wordTrie, word string) string {
+	for _, s := range stemSuffixes {
+		stem, ok := strings.CutSuffix(word, s.suffix)
+		if !ok || len(stem) < 2 {
+			continue
+		}
+		if base := stem + s.replace; trie.Search(base) {
+			return base
+		}
+		if s.replace == "" && doubledConsonant(stem) && trie.Search(stem[:len(stem)-1]) {
+			return stem[:len(stem)-1]
+		}
+	}
+	return ""
+}
+
+// doubledConsonant reports whether word ends in the same consonant twice,
+// as "runn" does.
+func doubledConsonant(word string) bool {
+	n := len(word)
+	return n >= 3 && word[n-1] == word[n-2] && !strings.ContainsRune("aeiou", rune(word[n-1]))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStemBase(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"jump", "bake", "party", "run", "box", "tall", "quick", "cat"} {
+		trie.Insert(word)
+	}
+
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"jumped", "jump"},
+		{"jumping", "jump"},
+		{"baked", "bake"},
+		{"baking", "bake"},
+		{"parties", "party"},
+		{"running", "run"},
+		{"boxes", "box"},
+		{"tallest", "tall"},
+		{"quickly", "quick"},
+		{"cats", "cat"},
+		{"jump", ""},
+		{"dogs", ""},
+		{"is", ""},
+	}
+	for _, tt := range tests {
+		if got := stemBase(trie, tt.word); got != tt.want {
+			t.Errorf("stemBase(%q) = %q, expected %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSolveTiles_Stem(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"jump", "bake"} {
+		trie.Insert(word)
+	}
+	tiles := []string{"jump", "ed", "ba", "ke", "d"}
+
+	results, loose, _, err := solveTiles(context.Background(), trie, tiles, options{})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	for _, r := range append(results, loose...) {
+		if r.Word == "jumped" || r.Word == "baked" {
+			t.Errorf("Expected %s only with stemming, got %+v", r.Word, r)
+		}
+	}
+
+	results, loose, _, err = solveTiles(context.Background(), trie, tiles, options{stem: true})
+	if err != nil {
+		t.Fatalf("solveTiles() error = %v", err)
+	}
+	for _, r := range results {
+		if r.Stem != "" {
+			t.Errorf("Expected stem matches kept apart from the words, got %+v", r)
+		}
+	}
+	for _, r := range loose {
+		if r.Score != 0 {
+			t.Errorf("Expected stem match %s to be unscored, got %d points", r.Word, r.Score)
+		}
+	}
+	stems := map[string]string{}
+	for _, r := range append(results, loose...) {
+		stems[r.Word] = r.Stem
+	}
+	want := map[string]string{"jump": "", "bake": "", "jumped": "jump", "baked": "bake"}
+	for word, stem := range want {
+		got, ok := stems[word]
+		if !ok {
+			t.Errorf("Expected %s among results %+v", word, results)
+		} else if got != stem {
+			t.Errorf("Expected %s to have stem %q, got %q", word, stem, got)
+		}
+	}
+
+	withColor(t, false)
+	var buf bytes.Buffer
+	if err := (textPrinter{}).PrintResults(&buf, []Result{{Word: "jumped", Stem: "jump"}}); err != nil {
+		t.Fatalf("PrintResults() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "jumped (not in dictionary; stem of jump)") {
+		t.Errorf("Expected the stem note in text output, got %q", buf.String())
+	}
+}
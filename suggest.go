@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// suggestion pairs a four-tile arrangement that is not a word with a
+// dictionary word one edit away, hinting that one tile was mistranscribed.
+type suggestion struct {
+	candidate string
+	tiles     []string
+	word      string
+}
+
+// suggestNearMisses returns, for every four-tile arrangement that is not a
+// word, the dictionary words within edit distance 1 of it. Like findMatches
+// it builds arrangements one tile at a time, abandoning one as soon as its
+// letters are more than one edit from every prefix in the trie, so only a
+// small share of the arrangements a board projects is ever visited. Each
+// candidate and near word pair is reported once, however many tile
+// sequences spell the candidate. If ctx is cancelled it returns the
+// suggestions found so far along with the context's error.
+func suggestNearMisses(ctx context.Context, trie wordTrie, tiles []string) ([]suggestion, error) {
+	s := &nearMissSearch{
+		ctx:   ctx,
+		tiles: tiles,
+		used:  make([]bool, len(tiles)),
+		seen:  make(map[string]bool),
+	}
+	s.search(nearStates(trie))
+	return s.suggestions, s.err
+}
+
+// suggestTiles runs suggestNearMisses under the settings in opts: it
+// refuses boards whose four-tile arrangements exceed --max-candidates and
+// stops after --timeout.
+func suggestTiles(ctx context.Context, trie wordTrie, tiles []string, opts options) ([]suggestion, error) {
+	tiles = dropEmptyTiles(tiles)
+	if err := checkCandidateLimit(len(tiles), quartileMaxTiles, opts.maxCandidates); err != nil {
+		return nil, err
+	}
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+	return suggestNearMisses(ctx, trie, tiles)
+}
+
+// nearState is a trie node reached by spelling the letters of a candidate
+// with at most one edit.
+type nearState struct {
+	node   trieCursor
+	word   string
+	edited bool
+}
+
+// nearStates returns the states before any letter is read: the root, and
+// every first letter a near word could add in front of the candidate.
+func nearStates(trie wordTrie) []nearState {
+	return withDeletions([]nearState{{node: trie.at("")}})
+}
+
+// withDeletions adds to states every child of an unedited state, the
+// near words with one more letter than the candidate at this point.
+func withDeletions(states []nearState) []nearState {
+	for _, st := range states {
+		if st.edited {
+			continue
+		}
+		st.node.children(func(char rune, child trieCursor) {
+			states = append(states, nearState{node: child, word: st.word + string(char), edited: true})
+		})
+	}
+	return states
+}
+
+// advance reads letters from every state, matching each letter or, while a
+// state has no edit yet, substituting or skipping it. It returns the states
+// that survive, none once no word is within one edit.
+func advance(states []nearState, letters string) []nearState {
+	for _, char := range letters {
+		var next []nearState
+		for _, st := range states {
+			if child := st.node.next(char); child != nil {
+				next = append(next, nearState{node: child, word: st.word + string(char), edited: st.edited})
+			}
+			if st.edited {
+				continue
+			}
+			next = append(next, nearState{node: st.node, word: st.word, edited: true})
+			st.node.children(func(other rune, child trieCursor) {
+				if other != char {
+					next = append(next, nearState{node: child, word: st.word + string(other), edited: true})
+				}
+			})
+		}
+		states = withDeletions(next)
+		if len(states) == 0 {
+			break
+		}
+	}
+	return states
+}
+
+// nearMissSearch is suggestNearMisses' depth-first search over four-tile
+// arrangements.
+type nearMissSearch struct {
+	ctx   context.Context
+	tiles []string
+
+	used        []bool
+	sequence    []string
+	seen        map[string]bool
+	suggestions []suggestion
+	err         error
+}
+
+// search extends the arrangement with every unused tile in turn.
+func (s *nearMissSearch) search(states []nearState) {
+	for i, tile := range s.tiles {
+		if s.err != nil {
+			return
+		}
+		if s.used[i] {
+			continue
+		}
+		if err := s.ctx.Err(); err != nil {
+			s.err = err
+			return
+		}
+
+		next := advance(states, tile)
+		if len(next) == 0 {
+			continue
+		}
+		s.sequence = append(s.sequence, tile)
+		if len(s.sequence) == quartileMaxTiles {
+			s.collect(next)
+		} else {
+			s.used[i] = true
+			s.search(next)
+			s.used[i] = false
+		}
+		s.sequence = s.sequence[:len(s.sequence)-1]
+	}
+}
+
+// collect records the near words the final states spell, unless the
+// candidate is itself a word.
+func (s *nearMissSearch) collect(states []nearState) {
+	var words []string
+	for _, st := range states {
+		if !st.node.isWord() {
+			continue
+		}
+		if !st.edited {
+			return
+		}
+		words = append(words, st.word)
+	}
+	slices.Sort(words)
+
+	candidate := strings.Join(s.sequence, "")
+	for _, word := range slices.Compact(words) {
+		key := candidate + " " + word
+		if s.seen[key] {
+			continue
+		}
+		s.seen[key] = true
+		s.suggestions = append(s.suggestions, suggestion{candidate: candidate, tiles: slices.Clone(s.sequence), word: word})
+	}
+}
+
+// hasQuartile reports whether any match uses the maximum number of tiles.
+func hasQuartile(matches []Result) bool {
+	for _, m := range matches {
+		if len(m.Tiles) == quartileMaxTiles {
+			return true
+		}
+	}
+	return false
+}
+
+// printSuggestions reports near-miss quartiles.
+func printSuggestions(w io.Writer, suggestions []suggestion) {
+	if len(suggestions) == 0 {
+		fmt.Fprintln(w, "No quartiles found and no near misses within one edit")
+		return
+	}
+
+	fmt.Fprintln(w, "No quartiles found. Did you mean:")
+	for _, s := range suggestions {
+		fmt.Fprintf(w, "  %s (%s) -> %s\n", s.candidate, strings.Join(s.tiles, "|"), s.word)
+	}
+}
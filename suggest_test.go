@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestSuggestNearMisses(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("quartile")
+
+	// "tyle" was mistranscribed; the real tile is "tile"
+	tiles := []string{"qu", "ar", "ti", "ly"}
+	matches, _, _ := findMatches(context.Background(), trie, tiles, 4, false, nil)
+	if hasQuartile(matches) {
+		t.Fatal("Expected no quartile for the mistranscribed board")
+	}
+
+	suggestions, err := suggestNearMisses(context.Background(), trie, tiles)
+	if err != nil {
+		t.Fatalf("suggestNearMisses() error = %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("Expected 1 suggestion, got %v", suggestions)
+	}
+	s := suggestions[0]
+	if s.candidate != "quartily" || s.word != "quartile" {
+		t.Errorf("Expected quartily -> quartile, got %s -> %s", s.candidate, s.word)
+	}
+
+	var buf bytes.Buffer
+	printSuggestions(&buf, suggestions)
+	if !strings.Contains(buf.String(), "quartily (qu|ar|ti|ly) -> quartile") {
+		t.Errorf("Expected suggestion line, got %q", buf.String())
+	}
+}
+
+func TestSuggestNearMisses_NoneWithinOneEdit(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("quartile")
+
+	suggestions, err := suggestNearMisses(context.Background(), trie, []string{"zz", "xx", "yy", "ww"})
+	if err != nil {
+		t.Fatalf("suggestNearMisses() error = %v", err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions, got %v", suggestions)
+	}
+
+	var buf bytes.Buffer
+	printSuggestions(&buf, suggestions)
+	if !strings.Contains(buf.String(), "no near misses") {
+		t.Errorf("Expected no-near-miss message, got %q", buf.String())
+	}
+}
+
+func TestSuggestNearMisses_ReportsEachPairOnce(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("quartile")
+
+	// The two "ly" tiles spell every candidate that uses one of them twice
+	tiles := []string{"qu", "ar", "ti", "ly", "ly"}
+	suggestions, err := suggestNearMisses(context.Background(), trie, tiles)
+	if err != nil {
+		t.Fatalf("suggestNearMisses() error = %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Errorf("Expected quartily -> quartile once, got %v", suggestions)
+	}
+}
+
+func TestSuggestNearMisses_MatchesBruteForce(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"castle", "castles", "cattle", "quartile", "quartiles", "tiles", "stale", "caste", "scale"} {
+		trie.Insert(word)
+	}
+	tiles := []string{"ca", "st", "le", "s", "tt", "qu", "ar", "ti", "ly", "es"}
+
+	// Checking every four-tile arrangement with FuzzySearch is the
+	// definition the pruned search must agree with
+	want := make(map[string]bool)
+	for _, combo := range combinations(tiles, quartileMaxTiles) {
+		for _, perm := range permutations(combo) {
+			candidate := strings.Join(perm, "")
+			if trie.Search(candidate) {
+				continue
+			}
+			for _, word := range trie.FuzzySearch(candidate, 1) {
+				want[candidate+" "+word] = true
+			}
+		}
+	}
+
+	suggestions, err := suggestNearMisses(context.Background(), trie, tiles)
+	if err != nil {
+		t.Fatalf("suggestNearMisses() error = %v", err)
+	}
+	got := make(map[string]bool)
+	for _, s := range suggestions {
+		key := s.candidate + " " + s.word
+		if got[key] {
+			t.Errorf("Duplicate suggestion %s", key)
+		}
+		got[key] = true
+	}
+	if len(want) == 0 {
+		t.Fatal("Expected the board to have near misses")
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("Pruned search found %v, brute force found %v", got, want)
+	}
+}
+
+func TestSuggestNearMisses_Cancelled(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("quartile")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := suggestNearMisses(ctx, trie, []string{"qu", "ar", "ti", "ly"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSuggestTiles_CandidateLimit(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("quartile")
+
+	_, err := suggestTiles(context.Background(), trie, []string{"qu", "ar", "ti", "ly"}, options{maxCandidates: 10})
+	if !errors.Is(err, ErrTooManyCandidates) {
+		t.Errorf("Expected ErrTooManyCandidates, got %v", err)
+	}
+}
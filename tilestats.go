@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// tileUsage is how many found words use one tile.
+type tileUsage struct {
+	Tile  string
+	Words int
+}
+
+// tileUsageCounts counts, for each distinct tile, how many matches use it,
+// counting a word once even if it uses the tile twice. The result is sorted
+// with the most used tiles first and ties left in puzzle order, so the hub
+// tiles that take part in the most words lead the list.
+func tileUsageCounts(tiles []string, matches []Result) []tileUsage {
+	counts := make(map[string]int)
+	for _, m := range matches {
+		seen := make(map[string]bool)
+		for _, tile := range m.Tiles {
+			if !seen[tile] {
+				seen[tile] = true
+				counts[tile]++
+			}
+		}
+	}
+
+	var usage []tileUsage
+	listed := make(map[string]bool)
+	for _, tile := range tiles {
+		if listed[tile] {
+			continue
+		}
+		listed[tile] = true
+		usage = append(usage, tileUsage{Tile: tile, Words: counts[tile]})
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		return usage[i].Words > usage[j].Words
+	})
+	return usage
+}
+
+// printTileStats reports how many found words use each tile.
+func printTileStats(w io.Writer, tiles []string, matches []Result) {
+	fmt.Fprintln(w, "Tile usage:")
+	for _, u := range tileUsageCounts(tiles, matches) {
+		fmt.Fprintf(w, "  %-8s %d word(s)\n", u.Tile, u.Words)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTileUsageCounts(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "cater", "at", "ate"} {
+		trie.Insert(word)
+	}
+
+	tiles := []string{"c", "at", "er", "e", "qzx"}
+	matches, _, _ := findMatches(context.Background(), trie, tiles, 4, false, nil)
+
+	// Tally the decompositions directly to compare against
+	want := make(map[string]int)
+	for _, m := range matches {
+		for _, tile := range m.Tiles {
+			want[tile]++
+		}
+	}
+
+	usage := tileUsageCounts(tiles, matches)
+	if len(usage) != len(tiles) {
+		t.Fatalf("Expected a count for each of %d tiles, got %v", len(tiles), usage)
+	}
+	for _, u := range usage {
+		if u.Words != want[u.Tile] {
+			t.Errorf("Tile %q used by %d word(s), expected %d", u.Tile, u.Words, want[u.Tile])
+		}
+	}
+	if usage[0].Tile != "at" || usage[len(usage)-1].Tile != "qzx" {
+		t.Errorf("Expected at first and qzx last, got %v", usage)
+	}
+
+	var buf bytes.Buffer
+	printTileStats(&buf, tiles, matches)
+	if !strings.Contains(buf.String(), "Tile usage:") || !strings.Contains(buf.String(), "qzx") {
+		t.Errorf("Expected a line per tile, got:\n%s", buf.String())
+	}
+}
+
+func TestTileUsageCounts_RepeatedTile(t *testing.T) {
+	matches := []Result{{Word: "papa", Tiles: []string{"pa", "pa"}}}
+
+	usage := tileUsageCounts([]string{"pa", "pa"}, matches)
+	if len(usage) != 1 || usage[0].Words != 1 {
+		t.Errorf("Expected pa listed once with 1 word, got %v", usage)
+	}
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// TrieNode represents a node in the trie data structure for efficient word lookup.
+//
+// Children for the lowercase ASCII letters a–z live in a fixed array indexed
+// by letter, which keeps Search and HasPrefix to one array load per rune for
+// typical Quartile tiles. Any other rune, such as an accented letter, falls
+// back to a map that is only created when first needed.
+type TrieNode struct {
+	letters [26]*TrieNode
+	others  map[rune]*TrieNode
+	IsEnd   bool
+
+	// arena batch-allocates the nodes created by Insert. Only roots made by
+	// NewTrieNode have one; nodes without one allocate individually.
+	arena *nodeArena
+}
+
+// NewTrieNode creates and initializes a new trie node.
+func NewTrieNode() *TrieNode {
+	return &TrieNode{
+		IsEnd: false,
+		arena: &nodeArena{},
+	}
+}
+
+// nodeSlabSize is how many nodes an arena allocates at once.
+const nodeSlabSize = 1024
+
+// nodeArena hands out trie nodes from slabs so loading a dictionary makes
+// one allocation per slab instead of one per node. Nodes removed by Delete
+// stay in their slab until the whole trie is released.
+type nodeArena struct {
+	slab []TrieNode
+}
+
+// newNode returns an empty node, starting a new slab when the current one is
+// used up. A nil arena allocates the node on its own.
+func (a *nodeArena) newNode() *TrieNode {
+	if a == nil {
+		return &TrieNode{}
+	}
+	if len(a.slab) == 0 {
+		a.slab = make([]TrieNode, nodeSlabSize)
+	}
+	node := &a.slab[0]
+	a.slab = a.slab[1:]
+	return node
+}
+
+// child returns the child reached by char, or nil if there is none.
+func (t *TrieNode) child(char rune) *TrieNode {
+	if char >= 'a' && char <= 'z' {
+		return t.letters[char-'a']
+	}
+	return t.others[char]
+}
+
+// setChild links node as the child reached by char.
+func (t *TrieNode) setChild(char rune, node *TrieNode) {
+	if char >= 'a' && char <= 'z' {
+		t.letters[char-'a'] = node
+		return
+	}
+	if t.others == nil {
+		t.others = make(map[rune]*TrieNode)
+	}
+	t.others[char] = node
+}
+
+// removeChild unlinks the child reached by char.
+func (t *TrieNode) removeChild(char rune) {
+	if char >= 'a' && char <= 'z' {
+		t.letters[char-'a'] = nil
+		return
+	}
+	delete(t.others, char)
+}
+
+// eachChild calls fn for every child, letters a–z first in order and then
+// any other runes in map order.
+func (t *TrieNode) eachChild(fn func(char rune, child *TrieNode)) {
+	for i, child := range t.letters {
+		if child != nil {
+			fn(rune('a'+i), child)
+		}
+	}
+	for char, child := range t.others {
+		fn(char, child)
+	}
+}
+
+// hasChildren reports whether any child is linked below this node.
+func (t *TrieNode) hasChildren() bool {
+	for _, child := range t.letters {
+		if child != nil {
+			return true
+		}
+	}
+	return len(t.others) > 0
+}
+
+// Insert adds a word to the trie and reports whether it was new, so loaders
+// can count distinct words. The empty string is not a word, since no tile
+// sequence could spell it, so inserting it does nothing and reports false.
+func (t *TrieNode) Insert(word string) bool {
+	if word == "" {
+		return false
+	}
+	node := t
+	for _, char := range word {
+		child := node.child(char)
+		if child == nil {
+			child = t.arena.newNode()
+			node.setChild(char, child)
+		}
+		node = child
+	}
+	added := !node.IsEnd
+	node.IsEnd = true
+	return added
+}
+
+// Reset empties the trie in place, removing every word, so the same root can
+// be refilled, as a reload does, without callers swapping in a new trie. On
+// a root made by NewTrieNode the arena's partly used slab is dropped too, so
+// the old nodes can be garbage collected.
+func (t *TrieNode) Reset() {
+	t.letters = [26]*TrieNode{}
+	t.others = nil
+	t.IsEnd = false
+	if t.arena != nil {
+		t.arena = &nodeArena{}
+	}
+}
+
+// Delete removes a word from the trie and prunes any nodes left without
+// words beneath them. It returns whether the word was present.
+// Words sharing a prefix with the deleted word are unaffected.
+func (t *TrieNode) Delete(word string) bool {
+	deleted, _ := t.delete([]rune(word))
+	return deleted
+}
+
+// delete removes the remaining runes of a word below this node. It reports
+// whether the word was removed and whether this node is now empty and can be
+// dropped by its parent.
+func (t *TrieNode) delete(word []rune) (deleted, prune bool) {
+	if len(word) == 0 {
+		if !t.IsEnd {
+			return false, false
+		}
+		t.IsEnd = false
+		return true, !t.hasChildren()
+	}
+
+	child := t.child(word[0])
+	if child == nil {
+		return false, false
+	}
+
+	deleted, prune = child.delete(word[1:])
+	if prune {
+		t.removeChild(word[0])
+	}
+	return deleted, deleted && !t.IsEnd && !t.hasChildren()
+}
+
+// Search returns true if the word exists in the trie. It is always false for
+// the empty string, which Insert never stores.
+func (t *TrieNode) Search(word string) bool {
+	if word == "" {
+		return false
+	}
+	node := t.find(word)
+	return node != nil && node.IsEnd
+}
+
+// InsertNormalized adds a word to the trie after folding it to lowercase,
+// matching how the dictionary loaders store words, and reports whether it
+// was new.
+func (t *TrieNode) InsertNormalized(word string) bool {
+	return t.Insert(normalizeWord(word))
+}
+
+// SearchNormalized returns true if the word exists in the trie, ignoring case.
+// It only finds words that were stored lowercase, as the loaders and
+// InsertNormalized do.
+func (t *TrieNode) SearchNormalized(word string) bool {
+	return t.Search(normalizeWord(word))
+}
+
+// HasPrefixNormalized returns true if any lowercase word in the trie begins
+// with prefix, ignoring the case of prefix.
+func (t *TrieNode) HasPrefixNormalized(prefix string) bool {
+	return t.HasPrefix(normalizeWord(prefix))
+}
+
+// normalizeWord folds a word to the lowercase form stored in the trie.
+func normalizeWord(word string) string {
+	return strings.ToLower(word)
+}
+
+// HasPrefix returns true if any word in the trie begins with prefix.
+func (t *TrieNode) HasPrefix(prefix string) bool {
+	return t.find(prefix) != nil
+}
+
+// CountPrefix returns the number of words in the trie that begin with prefix.
+func (t *TrieNode) CountPrefix(prefix string) int {
+	node := t.find(prefix)
+	if node == nil {
+		return 0
+	}
+	return node.countWords()
+}
+
+// WordCount returns the number of distinct words in the trie. A word
+// inserted more than once is counted once, so this is the authoritative size
+// of a loaded dictionary.
+func (t *TrieNode) WordCount() int {
+	return t.countWords()
+}
+
+// WordsWithPrefix returns every word in the trie that begins with prefix,
+// sorted alphabetically. An empty prefix returns every word.
+func (t *TrieNode) WordsWithPrefix(prefix string) []string {
+	node := t.find(prefix)
+	if node == nil {
+		return nil
+	}
+
+	var words []string
+	var collect func(node *TrieNode, word []rune)
+	collect = func(node *TrieNode, word []rune) {
+		if node.IsEnd {
+			words = append(words, string(word))
+		}
+		node.eachChild(func(char rune, child *TrieNode) {
+			collect(child, append(word[:len(word):len(word)], char))
+		})
+	}
+	collect(node, []rune(prefix))
+
+	sort.Strings(words)
+	return words
+}
+
+// Walk calls fn for every word in the trie in lexicographic order, the
+// order sort.Strings would give. Unlike WordsWithPrefix it never holds the
+// whole word list in memory, so it suits very large tries.
+func (t *TrieNode) Walk(fn func(word string)) {
+	var walk func(node *TrieNode, word []rune)
+	walk = func(node *TrieNode, word []rune) {
+		if node.IsEnd {
+			fn(string(word))
+		}
+		node.eachChildSorted(func(char rune, child *TrieNode) {
+			walk(child, append(word, char))
+		})
+	}
+	walk(t, nil)
+}
+
+// eachChildSorted calls fn for every child in rune order. Runes outside
+// a–z, such as an apostrophe or an accented letter, may sort before or
+// after the letters, so they are merged in rather than visited last.
+func (t *TrieNode) eachChildSorted(fn func(char rune, child *TrieNode)) {
+	if len(t.others) == 0 {
+		t.eachChild(fn)
+		return
+	}
+
+	chars := make([]rune, 0, len(t.others)+len(t.letters))
+	t.eachChild(func(char rune, _ *TrieNode) {
+		chars = append(chars, char)
+	})
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	for _, char := range chars {
+		fn(char, t.child(char))
+	}
+}
+
+// find returns the node reached by following prefix, or nil if no word
+// in the trie begins with it.
+func (t *TrieNode) find(prefix string) *TrieNode {
+	node := t
+	for _, char := range prefix {
+		node = node.child(char)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// countWords returns the number of complete words at or below this node.
+func (t *TrieNode) countWords() int {
+	count := 0
+	if t.IsEnd {
+		count++
+	}
+	t.eachChild(func(_ rune, child *TrieNode) {
+		count += child.countWords()
+	})
+	return count
+}
+
+// NodeCount returns the total number of nodes in the trie, including the root.
+func (t *TrieNode) NodeCount() int {
+	count := 1
+	t.eachChild(func(_ rune, child *TrieNode) {
+		count += child.NodeCount()
+	})
+	return count
+}
+
+// TrieStats summarizes the shape of a trie for monitoring its health.
+type TrieStats struct {
+	// Nodes is the total number of nodes, including the root.
+	Nodes int
+	// EndNodes is the number of nodes where a word ends, which is the
+	// number of words stored.
+	EndNodes int
+	// MaxDepth is the length in runes of the longest word.
+	MaxDepth int
+	// Branching maps each child count to how many nodes have that many
+	// children; Branching[0] counts the leaves.
+	Branching map[int]int
+}
+
+// Stats walks the trie once and returns its TrieStats.
+func (t *TrieNode) Stats() TrieStats {
+	stats := TrieStats{Branching: make(map[int]int)}
+	var walk func(node *TrieNode, depth int)
+	walk = func(node *TrieNode, depth int) {
+		stats.Nodes++
+		if node.IsEnd {
+			stats.EndNodes++
+			stats.MaxDepth = max(stats.MaxDepth, depth)
+		}
+		children := 0
+		node.eachChild(func(_ rune, child *TrieNode) {
+			children++
+			walk(child, depth+1)
+		})
+		stats.Branching[children]++
+	}
+	walk(t, 0)
+	return stats
+}
+
+// wordLengthCounts returns how many words of each rune length the trie holds.
+func (t *TrieNode) wordLengthCounts() map[int]int {
+	counts := make(map[int]int)
+	var walk func(node *TrieNode, depth int)
+	walk = func(node *TrieNode, depth int) {
+		if node.IsEnd {
+			counts[depth]++
+		}
+		node.eachChild(func(_ rune, child *TrieNode) {
+			walk(child, depth+1)
+		})
+	}
+	walk(t, 0)
+	return counts
+}
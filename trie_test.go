@@ -0,0 +1,456 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestTrieNode_CountPrefix(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "cats", "catalog", "dog"} {
+		trie.Insert(word)
+	}
+
+	tests := []struct {
+		prefix   string
+		expected int
+	}{
+		{"cat", 3},
+		{"cata", 1},
+		{"do", 1},
+		{"", 4},
+		{"zebra", 0},
+	}
+
+	for _, tt := range tests {
+		if got := trie.CountPrefix(tt.prefix); got != tt.expected {
+			t.Errorf("CountPrefix(%q) = %d, expected %d", tt.prefix, got, tt.expected)
+		}
+	}
+}
+
+func TestTrieNode_HasPrefix(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("quartile")
+
+	for _, prefix := range []string{"", "q", "quar", "quartile"} {
+		if !trie.HasPrefix(prefix) {
+			t.Errorf("Expected HasPrefix(%q) to be true", prefix)
+		}
+	}
+	for _, prefix := range []string{"x", "quartiles", "qa"} {
+		if trie.HasPrefix(prefix) {
+			t.Errorf("Expected HasPrefix(%q) to be false", prefix)
+		}
+	}
+}
+
+func TestTrieNode_Normalized(t *testing.T) {
+	trie := NewTrieNode()
+	trie.InsertNormalized("Hello")
+	trie.InsertNormalized("CAFÉ")
+
+	for _, word := range []string{"hello", "Hello", "HELLO", "café", "Café"} {
+		if !trie.SearchNormalized(word) {
+			t.Errorf("Expected SearchNormalized(%q) to be true", word)
+		}
+	}
+	if !trie.Search("hello") {
+		t.Error("Expected InsertNormalized to store the lowercase form")
+	}
+	if trie.Search("Hello") {
+		t.Error("Expected plain Search to remain case-sensitive")
+	}
+	if !trie.HasPrefixNormalized("HEL") || !trie.HasPrefixNormalized("caF") {
+		t.Error("Expected HasPrefixNormalized to ignore case")
+	}
+	if trie.HasPrefixNormalized("World") {
+		t.Error("Expected HasPrefixNormalized(\"World\") to be false")
+	}
+}
+
+func TestTrieNode_NodeCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		expected int
+	}{
+		{"empty trie", nil, 1},
+		{"single word", []string{"cat"}, 4},
+		{"shared prefix", []string{"cat", "car"}, 5},
+		{"word and extension", []string{"cat", "cats"}, 5},
+		{"disjoint words", []string{"ab", "cd"}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trie := NewTrieNode()
+			for _, word := range tt.words {
+				trie.Insert(word)
+			}
+			if got := trie.NodeCount(); got != tt.expected {
+				t.Errorf("NodeCount() = %d, expected %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTrieNode_Stats(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "car", "cats", "dog"} {
+		trie.Insert(word)
+	}
+
+	// root -> c, d; ca -> t, r; cat -> s; d -> o -> g
+	want := TrieStats{
+		Nodes:     9,
+		EndNodes:  4,
+		MaxDepth:  4,
+		Branching: map[int]int{0: 3, 1: 4, 2: 2},
+	}
+	if got := trie.Stats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats() = %+v, expected %+v", got, want)
+	}
+
+	empty := NewTrieNode().Stats()
+	if empty.Nodes != 1 || empty.EndNodes != 0 || empty.MaxDepth != 0 || empty.Branching[0] != 1 {
+		t.Errorf("Stats() of an empty trie = %+v, expected a single leaf root", empty)
+	}
+}
+
+func TestTrieNode_WordLengthCounts(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"at", "be", "cat", "café"} {
+		trie.Insert(word)
+	}
+
+	counts := trie.wordLengthCounts()
+	expected := map[int]int{2: 2, 3: 1, 4: 1}
+	if len(counts) != len(expected) {
+		t.Fatalf("wordLengthCounts() = %v, expected %v", counts, expected)
+	}
+	for length, count := range expected {
+		if counts[length] != count {
+			t.Errorf("wordLengthCounts()[%d] = %d, expected %d", length, counts[length], count)
+		}
+	}
+}
+
+func TestTrieNode_WordsWithPrefix(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"tile", "tiles", "tiler", "time", "quartile"} {
+		trie.Insert(word)
+	}
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"til", []string{"tile", "tiler", "tiles"}},
+		{"ti", []string{"tile", "tiler", "tiles", "time"}},
+		{"tiles", []string{"tiles"}},
+		{"", []string{"quartile", "tile", "tiler", "tiles", "time"}},
+		{"zz", nil},
+	}
+
+	for _, tt := range tests {
+		got := trie.WordsWithPrefix(tt.prefix)
+		if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("WordsWithPrefix(%q) = %v, expected %v", tt.prefix, got, tt.expected)
+		}
+	}
+}
+
+func TestTrieNode_Delete(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"tile", "tiles", "tiler", "time"} {
+		trie.Insert(word)
+	}
+	nodesBefore := trie.NodeCount()
+
+	if !trie.Delete("tiles") {
+		t.Error("Expected Delete(\"tiles\") to report the word existed")
+	}
+	if trie.Search("tiles") {
+		t.Error("Expected 'tiles' to be removed")
+	}
+	for _, word := range []string{"tile", "tiler", "time"} {
+		if !trie.Search(word) {
+			t.Errorf("Expected '%s' sharing a prefix to remain", word)
+		}
+	}
+	// Only the dangling 's' node should have been pruned
+	if got := trie.NodeCount(); got != nodesBefore-1 {
+		t.Errorf("NodeCount() = %d after delete, expected %d", got, nodesBefore-1)
+	}
+
+	// Deleting a word that is only a prefix keeps the longer words
+	if !trie.Delete("tile") {
+		t.Error("Expected Delete(\"tile\") to report the word existed")
+	}
+	if trie.Search("tile") || !trie.Search("tiler") {
+		t.Error("Expected 'tile' removed and 'tiler' kept")
+	}
+
+	if trie.Delete("ti") || trie.Delete("zebra") || trie.Delete("tile") {
+		t.Error("Expected Delete to report false for absent words")
+	}
+
+	// Removing the last words prunes back to the root
+	trie.Delete("tiler")
+	trie.Delete("time")
+	if got := trie.NodeCount(); got != 1 {
+		t.Errorf("NodeCount() = %d after deleting everything, expected 1", got)
+	}
+}
+
+func TestTrieArena_MatchesUnpooledTrie(t *testing.T) {
+	pooled := NewTrieNode()
+	unpooled := &TrieNode{}
+
+	// Enough words to span several slabs
+	var words []string
+	for i := 0; i < 3*nodeSlabSize; i++ {
+		word := fmt.Sprintf("w%dx", i)
+		words = append(words, word)
+		pooled.Insert(word)
+		unpooled.Insert(word)
+	}
+
+	if pooled.NodeCount() != unpooled.NodeCount() {
+		t.Errorf("NodeCount() = %d, expected %d", pooled.NodeCount(), unpooled.NodeCount())
+	}
+	for _, word := range words {
+		if !pooled.Search(word) {
+			t.Errorf("Expected to find %q", word)
+		}
+		if pooled.Search(word[:len(word)-1]) != unpooled.Search(word[:len(word)-1]) {
+			t.Errorf("Search(%q) differs between pooled and unpooled tries", word[:len(word)-1])
+		}
+		if !pooled.HasPrefix(word[:2]) {
+			t.Errorf("Expected prefix %q", word[:2])
+		}
+	}
+	if pooled.HasPrefix("x") || pooled.Search("w") {
+		t.Error("Expected no match for absent prefix or word")
+	}
+
+	// Deleting and reinserting still works with slab-allocated nodes
+	if !pooled.Delete("w7x") || pooled.Search("w7x") {
+		t.Error("Expected w7x to be deleted")
+	}
+	pooled.Insert("w7x")
+	if !pooled.Search("w7x") {
+		t.Error("Expected w7x after reinserting")
+	}
+}
+
+// BenchmarkTrieLoad compares building a trie from slab-allocated nodes with
+// allocating every node individually. Run with -benchmem to see allocations.
+func BenchmarkTrieLoad(b *testing.B) {
+	var words []string
+	for i := 0; i < 10000; i++ {
+		words = append(words, fmt.Sprintf("word%dtile%d", i, i%97))
+	}
+
+	b.Run("arena", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			trie := NewTrieNode()
+			for _, word := range words {
+				trie.Insert(word)
+			}
+		}
+	})
+	b.Run("individual", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			trie := &TrieNode{}
+			for _, word := range words {
+				trie.Insert(word)
+			}
+		}
+	})
+}
+
+func TestTrieNode_LetterAndFallbackChildren(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cafe", "café", "naïve", "zebra", "ab"} {
+		trie.Insert(word)
+	}
+
+	// ASCII letters use the fixed array
+	if trie.letters['c'-'a'] == nil || trie.letters['z'-'a'] == nil {
+		t.Error("Expected a-z children in the letter array")
+	}
+	if trie.others != nil {
+		t.Errorf("Expected no fallback map at the root, got %v", trie.others)
+	}
+
+	// Accented runes fall back to the map
+	caf := trie.find("caf")
+	if caf.child('é') == nil || caf.others['é'] == nil {
+		t.Error("Expected é in the fallback map")
+	}
+
+	for _, word := range []string{"cafe", "café", "naïve", "zebra", "ab"} {
+		if !trie.Search(word) {
+			t.Errorf("Expected to find %q", word)
+		}
+	}
+	if !trie.HasPrefix("naï") || trie.HasPrefix("naé") || trie.Search("caf") {
+		t.Error("Unexpected prefix or word match")
+	}
+
+	// Non-ASCII uppercase and symbols never reach the letter array
+	trie.Insert("A1")
+	if !trie.Search("A1") || trie.others['A'] == nil {
+		t.Error("Expected uppercase and digits in the fallback map")
+	}
+
+	if !trie.Delete("café") || trie.Search("café") || !trie.Search("cafe") {
+		t.Error("Expected café deleted and cafe kept")
+	}
+	if len(caf.others) != 0 {
+		t.Errorf("Expected é pruned from the fallback map, got %v", caf.others)
+	}
+
+	want := []string{"A1", "ab", "cafe", "naïve", "zebra"}
+	if got := trie.WordsWithPrefix(""); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("WordsWithPrefix(\"\") = %v, expected %v", got, want)
+	}
+}
+
+// BenchmarkTrieLookup measures Search and HasPrefix on ASCII words, the path
+// the letter array speeds up.
+func BenchmarkTrieLookup(b *testing.B) {
+	trie := NewTrieNode()
+	words := []string{"quartile", "quarter", "quart", "artist", "tile", "tiles", "startle", "benchmark"}
+	for _, word := range words {
+		trie.Insert(word)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, word := range words {
+			trie.Search(word)
+			trie.HasPrefix(word[:3])
+		}
+	}
+}
+
+func TestTrieNode_Walk(t *testing.T) {
+	words := []string{"cat", "o'clock", "café", "ca", "cats", "zebra", "a", "oat", "résumé", "x-ray", "xylem"}
+	trie := NewTrieNode()
+	for _, word := range words {
+		trie.Insert(word)
+	}
+
+	var visited []string
+	trie.Walk(func(word string) {
+		visited = append(visited, word)
+	})
+
+	want := append([]string{}, words...)
+	sort.Strings(want)
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("Walk visited %v, expected %v", visited, want)
+	}
+
+	empty := 0
+	NewTrieNode().Walk(func(string) { empty++ })
+	if empty != 0 {
+		t.Errorf("Expected an empty trie to visit nothing, got %d words", empty)
+	}
+}
+
+func TestTrieNode_Reset(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "cats", "café", "zebra"} {
+		trie.Insert(word)
+	}
+
+	trie.Reset()
+	if words := trie.WordsWithPrefix(""); len(words) != 0 {
+		t.Errorf("Expected a reset trie to hold no words, got %v", words)
+	}
+	if trie.Search("cat") || trie.HasPrefix("ca") {
+		t.Error("Expected cat to be gone after Reset")
+	}
+	if got := trie.NodeCount(); got != 1 {
+		t.Errorf("Expected only the root after Reset, got %d nodes", got)
+	}
+
+	for _, word := range []string{"dog", "café"} {
+		trie.Insert(word)
+	}
+	if got, want := trie.WordsWithPrefix(""), []string{"café", "dog"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected a refilled trie to hold %v, got %v", want, got)
+	}
+}
+
+func TestTrieNode_WordCount(t *testing.T) {
+	trie := NewTrieNode()
+	if got := trie.WordCount(); got != 0 {
+		t.Errorf("Expected an empty trie to hold 0 words, got %d", got)
+	}
+
+	inserted := []string{"cat", "cats", "cat", "café", "ca", "cats", "zebra", ""}
+	distinct := make(map[string]bool)
+	for _, word := range inserted {
+		trie.Insert(word)
+		if word != "" {
+			distinct[word] = true
+		}
+	}
+	if got := trie.WordCount(); got != len(distinct) {
+		t.Errorf("WordCount() = %d, expected %d distinct words", got, len(distinct))
+	}
+
+	trie.Delete("cats")
+	if got := trie.WordCount(); got != len(distinct)-1 {
+		t.Errorf("Expected WordCount() %d after Delete, got %d", len(distinct)-1, got)
+	}
+}
+
+func TestTrieNode_WordCount_DictionaryForms(t *testing.T) {
+	// "dog" is both a noun and a verb, so their generated forms overlap
+	dictionary := "s(102084071,1,'dog',n,1,42).\ns(202004701,1,'dog',v,1,0).\n"
+	trie := NewTrieNode()
+	if _, err := loadDictionaryReader(context.Background(), strings.NewReader(dictionary), trie, false); err != nil {
+		t.Fatalf("loadDictionaryReader() error = %v", err)
+	}
+
+	distinct := make(map[string]bool)
+	for _, partOfSpeech := range []string{"n", "v"} {
+		for _, form := range generatedForms("dog", partOfSpeech, loadOptions{}) {
+			distinct[form] = true
+		}
+	}
+	if got := trie.WordCount(); got != len(distinct) {
+		t.Errorf("WordCount() = %d, expected %d distinct words", got, len(distinct))
+	}
+}
+
+func TestTrieNode_InsertReportsNewWords(t *testing.T) {
+	trie := NewTrieNode()
+	count := 0
+	for _, word := range []string{"cat", "cat", "cats", "ca", "cat"} {
+		if trie.Insert(word) {
+			count++
+		}
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 new words counted, got %d", count)
+	}
+	if trie.Insert("") {
+		t.Error("Expected inserting the empty string to report false")
+	}
+	if !trie.InsertNormalized("DOG") || trie.InsertNormalized("dog") {
+		t.Error("Expected InsertNormalized to report only the first dog as new")
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrBadTrieFile is returned by OpenTrieFile when a file is not a trie file
+// written by BuildTrieFile, or its nodes do not fit together as
+// BuildTrieFile lays them out, as when the file has been truncated.
+var ErrBadTrieFile = errors.New("not a valid trie file")
+
+// trieFileMagic starts every trie file and names the format version.
+var trieFileMagic = []byte("AQTRIE1\n")
+
+// A trie file lays the nodes out in depth-first order after the magic, root
+// first, so it can be queried in place without decoding. Each node is a flags
+// byte (trieFileEnd when a word ends there), a little-endian uint32 child
+// count, and that many (rune, offset) pairs of uint32s sorted by rune, where
+// offset is the child's position from the start of the file.
+const (
+	trieFileEnd        = 1
+	trieFileNodeHeader = 5
+	trieFileChildSize  = 8
+)
+
+// BuildTrieFile writes trie to path in the trie file format so OpenTrieFile
+// can later map it instead of loading the dictionary again.
+func BuildTrieFile(trie *TrieNode, path string) error {
+	// Offsets are assigned in the same depth-first order the nodes are written
+	offsets := make(map[*TrieNode]uint32)
+	size := uint64(len(trieFileMagic))
+	var layout func(node *TrieNode)
+	layout = func(node *TrieNode) {
+		offsets[node] = uint32(size)
+		children := 0
+		node.eachChild(func(rune, *TrieNode) { children++ })
+		size += trieFileNodeHeader + uint64(children)*trieFileChildSize
+		node.eachChildSorted(func(_ rune, child *TrieNode) { layout(child) })
+	}
+	layout(trie)
+	if size > 1<<32-1 {
+		return fmt.Errorf("trie needs %d bytes, more than a trie file can hold", size)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating trie file: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	w.Write(trieFileMagic)
+
+	var write func(node *TrieNode)
+	write = func(node *TrieNode) {
+		var header [trieFileNodeHeader]byte
+		if node.IsEnd {
+			header[0] = trieFileEnd
+		}
+		var children []*TrieNode
+		var chars []rune
+		node.eachChildSorted(func(char rune, child *TrieNode) {
+			chars = append(chars, char)
+			children = append(children, child)
+		})
+		binary.LittleEndian.PutUint32(header[1:], uint32(len(children)))
+		w.Write(header[:])
+		for i, child := range children {
+			var entry [trieFileChildSize]byte
+			binary.LittleEndian.PutUint32(entry[:4], uint32(chars[i]))
+			binary.LittleEndian.PutUint32(entry[4:], offsets[child])
+			w.Write(entry[:])
+		}
+		for _, child := range children {
+			write(child)
+		}
+	}
+	write(trie)
+
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("writing trie file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("writing trie file: %w", err)
+	}
+	return nil
+}
+
+// TrieFile is a read-only trie backed by a file from BuildTrieFile. Where
+// the platform supports it the file is memory-mapped and queried in place
+// rather than decoded, so the OS pages in only the nodes a solve visits. It
+// implements wordTrie, so a --dictionary trie file is solved against
+// directly; only runs that edit the words, such as with --allowlist, copy
+// it into a TrieNode with Load.
+type TrieFile struct {
+	data  []byte
+	close func() error
+}
+
+// OpenTrieFile maps the trie file at path for querying. Call Close when done
+// to release the mapping. Every node is checked when the file is opened, so
+// a damaged file is reported as ErrBadTrieFile instead of failing a lookup.
+// A gzip-compressed trie file, such as words.trie.gz, cannot be queried in
+// place, so it is decompressed into memory instead.
+func OpenTrieFile(path string) (*TrieFile, error) {
+	data, release, err := mapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening trie file: %w", dictionaryOpenError(path, err))
+	}
+	if bytes.HasPrefix(data, gzipMagic) {
+		data, err = gunzip(data)
+		release()
+		if err != nil {
+			return nil, fmt.Errorf("decompressing trie file %s: %w", path, err)
+		}
+		release = func() error { return nil }
+	}
+	mapped, err := newTrieFile(data)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	mapped.close = release
+	return mapped, nil
+}
+
+// gunzip returns the decompressed contents of a gzip stream.
+func gunzip(compressed []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// newTrieFile wraps the bytes of a trie file already in memory, such as one
+// uploaded to the browser build, after checking them with validateTrieFile.
+func newTrieFile(data []byte) (*TrieFile, error) {
+	if !bytes.HasPrefix(data, trieFileMagic) {
+		return nil, ErrBadTrieFile
+	}
+	if err := validateTrieFile(data); err != nil {
+		return nil, err
+	}
+	return &TrieFile{data: data}, nil
+}
+
+// validateTrieFile checks that data holds exactly the depth-first node layout
+// BuildTrieFile writes: every node and child entry lies inside data, each
+// child offset is where that child's subtree starts, children are sorted by
+// rune, and nothing follows the last node. Any file that passes is a tree
+// whose lookups and walks stay in bounds. It scans the nodes in file order
+// with an explicit stack, so a deep chain of nodes cannot overflow the Go
+// stack.
+func validateTrieFile(data []byte) error {
+	// header returns the end of the child entries of the node at pos, or
+	// false if they do not fit in data
+	header := func(pos uint64) (uint64, bool) {
+		if pos+trieFileNodeHeader > uint64(len(data)) || data[pos]&^trieFileEnd != 0 {
+			return 0, false
+		}
+		count := uint64(binary.LittleEndian.Uint32(data[pos+1:]))
+		end := pos + trieFileNodeHeader + count*trieFileChildSize
+		return end, end <= uint64(len(data))
+	}
+
+	type frame struct {
+		node, count, next uint32
+		last              rune
+	}
+	t := TrieFile{data: data}
+	root := uint64(len(trieFileMagic))
+	pos, ok := header(root)
+	if !ok {
+		return ErrBadTrieFile
+	}
+	stack := []frame{{node: uint32(root), count: t.childCount(uint32(root))}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == top.count {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		char, offset := t.childAt(top.node, top.next)
+		if uint64(offset) != pos || (top.next > 0 && char <= top.last) {
+			return ErrBadTrieFile
+		}
+		top.next++
+		top.last = char
+		if pos, ok = header(pos); !ok {
+			return ErrBadTrieFile
+		}
+		stack = append(stack, frame{node: offset, count: t.childCount(offset)})
+	}
+	if pos != uint64(len(data)) {
+		return ErrBadTrieFile
+	}
+	return nil
+}
+
+// loadTrieFile copies every word of the trie file at path into trie and
+// returns how many were loaded. Copying stops with the context's error once
+// ctx is cancelled.
+func loadTrieFile(ctx context.Context, path string, trie *TrieNode) (int, error) {
+	mapped, err := OpenTrieFile(path)
+	if err != nil {
+		return 0, err
+	}
+	defer mapped.Close()
+	return mapped.load(ctx, trie)
+}
+
+// Close releases the file mapping. The TrieFile must not be used afterwards.
+func (t *TrieFile) Close() error {
+	if t.close == nil {
+		return nil
+	}
+	err := t.close()
+	t.data, t.close = nil, nil
+	return err
+}
+
+// Search returns true if the word exists in the trie.
+func (t *TrieFile) Search(word string) bool {
+	node, ok := t.find(word)
+	return ok && t.data[node]&trieFileEnd != 0
+}
+
+// HasPrefix returns true if any word in the trie begins with prefix.
+func (t *TrieFile) HasPrefix(prefix string) bool {
+	_, ok := t.find(prefix)
+	return ok
+}
+
+// CountPrefix returns the number of words in the trie that begin with prefix.
+func (t *TrieFile) CountPrefix(prefix string) int {
+	node, ok := t.find(prefix)
+	if !ok {
+		return 0
+	}
+	count := 0
+	t.walkFrom(node, nil, func(string) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Walk calls fn for every word in the trie in lexicographic order, as
+// TrieNode.Walk does.
+func (t *TrieFile) Walk(fn func(word string)) {
+	t.walkFrom(uint32(len(trieFileMagic)), nil, func(word string) bool {
+		fn(word)
+		return true
+	})
+}
+
+// walkFrom calls fn for every word at or below node, in lexicographic order,
+// where word holds the letters leading to node. It stops, returning false,
+// as soon as fn returns false.
+func (t *TrieFile) walkFrom(node uint32, word []rune, fn func(word string) bool) bool {
+	if t.data[node]&trieFileEnd != 0 && !fn(string(word)) {
+		return false
+	}
+	for i := uint32(0); i < t.childCount(node); i++ {
+		char, child := t.childAt(node, i)
+		if !t.walkFrom(child, append(word, char), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Load inserts every word of the file into trie, for code that needs a
+// mutable TrieNode, and returns how many were not already there.
+func (t *TrieFile) Load(trie *TrieNode) int {
+	count, _ := t.load(context.Background(), trie)
+	return count
+}
+
+// load is Load, stopping with the context's error once ctx is cancelled.
+func (t *TrieFile) load(ctx context.Context, trie *TrieNode) (int, error) {
+	count := 0
+	var err error
+	t.walkFrom(uint32(len(trieFileMagic)), nil, func(word string) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		if trie.Insert(word) {
+			count++
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("loading stopped after %d words: %w", count, err)
+	}
+	return count, nil
+}
+
+// find returns the offset of the node reached by following prefix, or false
+// if no word begins with it.
+func (t *TrieFile) find(prefix string) (uint32, bool) {
+	node := uint32(len(trieFileMagic))
+	for _, char := range prefix {
+		child, ok := t.child(node, char)
+		if !ok {
+			return 0, false
+		}
+		node = child
+	}
+	return node, true
+}
+
+// child binary searches node's sorted children for char.
+func (t *TrieFile) child(node uint32, char rune) (uint32, bool) {
+	lo, hi := uint32(0), t.childCount(node)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		c, offset := t.childAt(node, mid)
+		switch {
+		case c == char:
+			return offset, true
+		case c < char:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return 0, false
+}
+
+// childCount returns how many children the node at offset node has.
+func (t *TrieFile) childCount(node uint32) uint32 {
+	return binary.LittleEndian.Uint32(t.data[node+1:])
+}
+
+// childAt returns the rune and offset of the node's i-th child.
+func (t *TrieFile) childAt(node, i uint32) (rune, uint32) {
+	entry := t.data[node+trieFileNodeHeader+i*trieFileChildSize:]
+	return rune(binary.LittleEndian.Uint32(entry)), binary.LittleEndian.Uint32(entry[4:])
+}
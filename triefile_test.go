@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTrieFile_BuildAndOpen(t *testing.T) {
+	trie := NewTrieNode()
+	words := []string{"cat", "cater", "caterpillar", "dog", "café", "at"}
+	for _, word := range words {
+		trie.Insert(word)
+	}
+
+	path := filepath.Join(t.TempDir(), "words.trie")
+	if err := BuildTrieFile(trie, path); err != nil {
+		t.Fatalf("BuildTrieFile() error = %v", err)
+	}
+
+	mapped, err := OpenTrieFile(path)
+	if err != nil {
+		t.Fatalf("OpenTrieFile() error = %v", err)
+	}
+	defer mapped.Close()
+
+	for _, word := range words {
+		if !mapped.Search(word) {
+			t.Errorf("Search(%q) = false, expected true", word)
+		}
+	}
+	for _, word := range []string{"ca", "cats", "caf", "", "zebra"} {
+		if mapped.Search(word) {
+			t.Errorf("Search(%q) = true, expected false", word)
+		}
+	}
+	if !mapped.HasPrefix("caterp") || !mapped.HasPrefix("caf") || mapped.HasPrefix("cb") {
+		t.Error("HasPrefix() disagrees with the source trie")
+	}
+
+	var walked []string
+	mapped.Walk(func(word string) { walked = append(walked, word) })
+	want := slices.Clone(words)
+	slices.Sort(want)
+	if !slices.Equal(walked, want) {
+		t.Errorf("Walk() = %v, expected %v", walked, want)
+	}
+
+	loaded := NewTrieNode()
+	if n := mapped.Load(loaded); n != len(words) || !loaded.Search("caterpillar") {
+		t.Errorf("Load() inserted %d words, expected %d", n, len(words))
+	}
+}
+
+func TestOpenTrieFile_RejectsOtherFiles(t *testing.T) {
+	path := writeTempFile(t, "words.txt", "cat\ndog\n")
+	if _, err := OpenTrieFile(path); !errors.Is(err, ErrBadTrieFile) {
+		t.Errorf("Expected ErrBadTrieFile for a word list, got %v", err)
+	}
+
+	empty := writeTempFile(t, "empty.trie", "")
+	if _, err := OpenTrieFile(empty); !errors.Is(err, ErrBadTrieFile) {
+		t.Errorf("Expected ErrBadTrieFile for an empty file, got %v", err)
+	}
+}
+
+// buildTrieFileBytes returns the trie file BuildTrieFile writes for words.
+func buildTrieFileBytes(t *testing.T, words ...string) []byte {
+	t.Helper()
+	trie := NewTrieNode()
+	for _, word := range words {
+		trie.Insert(word)
+	}
+	path := filepath.Join(t.TempDir(), "words.trie")
+	if err := BuildTrieFile(trie, path); err != nil {
+		t.Fatalf("BuildTrieFile() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestNewTrieFile_RejectsDamagedFiles(t *testing.T) {
+	data := buildTrieFileBytes(t, "cat", "cater", "dog", "café")
+	if _, err := newTrieFile(data); err != nil {
+		t.Fatalf("newTrieFile() error = %v for an intact file", err)
+	}
+
+	for size := len(trieFileMagic); size < len(data); size++ {
+		if _, err := newTrieFile(data[:size]); !errors.Is(err, ErrBadTrieFile) {
+			t.Errorf("Expected ErrBadTrieFile for the file truncated to %d bytes, got %v", size, err)
+		}
+	}
+	if _, err := newTrieFile(append(slices.Clone(data), 0)); !errors.Is(err, ErrBadTrieFile) {
+		t.Errorf("Expected ErrBadTrieFile for trailing bytes, got %v", err)
+	}
+
+	// Corrupting any single byte after the magic either leaves a file that
+	// still validates, which must then be safe to walk, or is rejected
+	for i := len(trieFileMagic); i < len(data); i++ {
+		for _, value := range []byte{0, 0xff, data[i] + 1} {
+			damaged := slices.Clone(data)
+			damaged[i] = value
+			mapped, err := newTrieFile(damaged)
+			if err != nil {
+				if !errors.Is(err, ErrBadTrieFile) {
+					t.Errorf("Expected ErrBadTrieFile with byte %d set to %#x, got %v", i, value, err)
+				}
+				continue
+			}
+			mapped.Walk(func(string) {})
+			mapped.Search("cater")
+		}
+	}
+}
+
+func TestRunWithOptions_TruncatedTrieFile(t *testing.T) {
+	data := buildTrieFileBytes(t, "castle", "cast")
+	dictPath := writeTempFile(t, "words.trie", string(data[:len(data)-3]))
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nst\nle\n")
+
+	err := runWithOptions(context.Background(), options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}}, io.Discard)
+	if !errors.Is(err, ErrBadTrieFile) {
+		t.Errorf("Expected ErrBadTrieFile for a truncated trie file, got %v", err)
+	}
+}
+
+// TestTrieFile_SolvesInPlace checks that solving against a mapped trie file
+// finds exactly what solving against the trie it was built from finds,
+// including the stem and near-miss matches that walk the trie's nodes.
+func TestTrieFile_SolvesInPlace(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "cats", "castle", "cast", "jump", "at", "sea", "seat"} {
+		trie.Insert(word)
+	}
+	path := filepath.Join(t.TempDir(), "words.trie")
+	if err := BuildTrieFile(trie, path); err != nil {
+		t.Fatalf("BuildTrieFile() error = %v", err)
+	}
+	mapped, err := OpenTrieFile(path)
+	if err != nil {
+		t.Fatalf("OpenTrieFile() error = %v", err)
+	}
+	defer mapped.Close()
+
+	tiles := []string{"c", "at", "s", "ca", "st", "lx", "jump", "ed", "se"}
+	opts := options{stem: true, partialLastTile: true}
+	want, wantLoose, _, err := solveTiles(context.Background(), trie, tiles, opts)
+	if err != nil {
+		t.Fatalf("solveTiles() on the trie error = %v", err)
+	}
+	got, gotLoose, _, err := solveTiles(context.Background(), mapped, tiles, opts)
+	if err != nil {
+		t.Fatalf("solveTiles() on the trie file error = %v", err)
+	}
+	if len(want) == 0 || len(wantLoose) == 0 {
+		t.Fatal("Expected the board to yield words and loose matches")
+	}
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(gotLoose, wantLoose) {
+		t.Errorf("Trie file found %+v and %+v, expected %+v and %+v", got, gotLoose, want, wantLoose)
+	}
+	if n := mapped.CountPrefix("ca"); n != trie.CountPrefix("ca") {
+		t.Errorf("CountPrefix(ca) = %d, expected %d", n, trie.CountPrefix("ca"))
+	}
+}
+
+func TestLoadSolverDictionary_TrieFile(t *testing.T) {
+	path := writeTempFile(t, "words.trie", string(buildTrieFileBytes(t, "cat", "dog")))
+
+	trie, count, err := loadSolverDictionary(context.Background(), options{dictionaryPath: path})
+	if err != nil {
+		t.Fatalf("loadSolverDictionary() error = %v", err)
+	}
+	mapped, ok := trie.(*TrieFile)
+	if !ok {
+		t.Fatalf("Expected the trie file to be queried in place, got %T", trie)
+	}
+	mapped.Close()
+	if count != 2 {
+		t.Errorf("Expected 2 words, got %d", count)
+	}
+
+	// Editing the words needs an in-memory trie
+	allowlist := writeTempFile(t, "allow.txt", "yeet\n")
+	trie, count, err = loadSolverDictionary(context.Background(), options{dictionaryPath: path, allowlistPath: allowlist})
+	if err != nil {
+		t.Fatalf("loadSolverDictionary() with an allowlist error = %v", err)
+	}
+	if _, ok := trie.(*TrieNode); !ok || count != 3 || !trie.Search("yeet") {
+		t.Errorf("Expected a TrieNode holding 3 words, got %T with %d", trie, count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := loadSolverDictionary(ctx, options{dictionaryPath: path}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled opening the trie file, got %v", err)
+	}
+	if _, err := loadTrieFile(ctx, path, NewTrieNode()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled copying the trie file, got %v", err)
+	}
+}
+
+func TestRunWithOptions_GzippedTrieFile(t *testing.T) {
+	dictPath := writeGzipFile(t, "words.trie.gz", string(buildTrieFileBytes(t, "castle", "cast")))
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nst\nle\n")
+
+	var buf bytes.Buffer
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, format: outputQuiet, diagnostics: io.Discard}
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() error = %v", err)
+	}
+	if words := strings.Fields(buf.String()); !slices.Equal(words, []string{"cast", "castle"}) {
+		t.Errorf("Expected cast and castle from the gzipped trie file, got %v", words)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"context"
+	"syscall/js"
+)
+
+// main registers a global solve(tilesJSON, dictBytes) function for
+// JavaScript and then blocks so it stays callable. dictBytes is a
+// Uint8Array holding WordNet facts, a newline-delimited wordlist, or a trie
+// file from build-cache. solve returns the results as a JSON string, or an
+// object with an error message; a malformed dictionary is an error, not a
+// panic that would stop the runtime.
+func main() {
+	js.Global().Set("solve", js.FuncOf(func(_ js.Value, args []js.Value) any {
+		if len(args) != 2 {
+			return map[string]any{"error": "solve expects (tilesJSON, dictBytes)"}
+		}
+		dictionary := make([]byte, args[1].Get("length").Int())
+		js.CopyBytesToGo(dictionary, args[1])
+
+		results, err := solveJSON(context.Background(), args[0].String(), dictionary)
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		return results
+	}))
+	select {}
+}
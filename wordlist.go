@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dictionary formats understood by loadDictionaryFile.
+const (
+	formatAuto    = "auto"
+	formatWordNet = "wordnet"
+	formatPlain   = "plain"
+	formatScowl   = "scowl"
+	formatTrie    = "trie"
+)
+
+// gzipMagic is the two-byte header that starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// dictionaryReader reads a possibly decompressed dictionary file and closes
+// every layer when done.
+type dictionaryReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *dictionaryReader) Close() error {
+	var firstErr error
+	for _, closer := range r.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// openDictionary opens a dictionary or word list file, transparently
+// decompressing it when it starts with the gzip magic bytes, so a
+// wn_s.pl.gz can be used without unpacking it first.
+func openDictionary(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, dictionaryOpenError(path, err)
+	}
+
+	buffered := bufio.NewReader(file)
+	if magic, _ := buffered.Peek(len(gzipMagic)); !bytes.Equal(magic, gzipMagic) {
+		return &dictionaryReader{Reader: buffered, closers: []io.Closer{file}}, nil
+	}
+
+	decompressed, err := gzip.NewReader(buffered)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("reading gzip header of %s: %w", path, err)
+	}
+	return &dictionaryReader{Reader: decompressed, closers: []io.Closer{decompressed, file}}, nil
+}
+
+// dictionaryOpenError explains why a dictionary or word list at path could not
+// be opened. A permission error becomes ErrDictionaryDenied with a hint on
+// fixing it, so it is not mistaken for a missing file; any other error is
+// returned as is.
+func dictionaryOpenError(path string, err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return fmt.Errorf("%w: %s (make it readable with chmod a+r, or check the permissions of its directory)", ErrDictionaryDenied, path)
+	}
+	return err
+}
+
+// maxLineBytes is the longest line the dictionary, word list, and puzzle
+// readers accept, well past bufio.Scanner's 64KB default so a stray huge line
+// in a malformed file is read (and usually skipped) instead of aborting.
+const maxLineBytes = 16 << 20
+
+// newLineScanner returns a line scanner over r that accepts lines up to
+// maxLineBytes long.
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	return scanner
+}
+
+// resolveDictionaryFormat validates a requested format and resolves "auto"
+// (or an empty string) by inspecting the file.
+func resolveDictionaryFormat(dictionaryPath, format string) (string, error) {
+	switch format {
+	case "", formatAuto:
+		return detectDictionaryFormat(dictionaryPath)
+	case formatWordNet, formatPlain, formatScowl, formatTrie:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown dictionary format %q (expected auto, wordnet, plain, scowl, or trie)", format)
+	}
+}
+
+// detectDictionaryFormat guesses the format of a dictionary file.
+// Files with a .pl extension, or .pl.gz, are treated as WordNet and .trie
+// files as trie files; anything else is sniffed from its first non-empty
+// line.
+func detectDictionaryFormat(dictionaryPath string) (string, error) {
+	name := strings.TrimSuffix(strings.ToLower(dictionaryPath), ".gz")
+	switch filepath.Ext(name) {
+	case ".pl":
+		return formatWordNet, nil
+	case ".trie":
+		return formatTrie, nil
+	}
+
+	dictionaryFile, err := openDictionary(dictionaryPath)
+	if err != nil {
+		return "", fmt.Errorf("opening dictionary file: %w", err)
+	}
+	defer dictionaryFile.Close()
+
+	return sniffDictionaryFormat(dictionaryFile)
+}
+
+// sniffDictionaryFormat reads from r up to its first non-empty line and
+// reports formatWordNet if that line is a WordNet fact, formatTrie if it is
+// the trie file magic, or formatPlain.
+func sniffDictionaryFormat(r io.Reader) (string, error) {
+	scanner := newLineScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if wordNetLine.MatchString(line) {
+			return formatWordNet, nil
+		}
+		if line == strings.TrimSpace(string(trieFileMagic)) {
+			return formatTrie, nil
+		}
+		return formatPlain, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("scanning dictionary file: %w", err)
+	}
+
+	return formatPlain, nil
+}
+
+// loadDictionaryFile loads a dictionary into the trie with the loader for the
+// given format, detecting the format first when it is "auto" or empty.
+// SCOWL/aspell lists are already fully inflected, so like plain wordlists
+// each line is taken as a final surface form with no generated forms. Trie
+// files hold an already built dictionary and are copied in as they are.
+// Loading stops with the context's error once ctx is cancelled.
+func loadDictionaryFile(ctx context.Context, dictionaryPath, format string, trie *TrieNode, opts loadOptions) (int, error) {
+	format, err := resolveDictionaryFormat(dictionaryPath, format)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Info("detected dictionary format", "path", dictionaryPath, "format", format)
+
+	switch format {
+	case formatPlain, formatScowl:
+		return loadPlainWordlist(ctx, dictionaryPath, trie, opts)
+	case formatTrie:
+		return loadTrieFile(ctx, dictionaryPath, trie)
+	default:
+		return loadWordNet(ctx, dictionaryPath, trie, opts)
+	}
+}
+
+// loadPlainWordlist loads a newline-delimited wordlist (such as
+// /usr/share/dict/words or a SCOWL list) into the trie. Each line is inserted
+// as-is after lowercasing; no part-of-speech forms are generated. Capitalized
+// entries are skipped as proper nouns unless opts.includeProper is set,
+// matching the WordNet loader, and possessives such as "cat's" are skipped
+// since no tile holds an apostrophe.
+//
+// Returns the number of words loaded and any error encountered.
+func loadPlainWordlist(ctx context.Context, dictionaryPath string, trie *TrieNode, opts loadOptions) (int, error) {
+	dictionaryFile, err := openDictionary(dictionaryPath)
+	if err != nil {
+		return 0, fmt.Errorf("opening dictionary file: %w", err)
+	}
+	defer dictionaryFile.Close()
+
+	return readPlainWordlist(ctx, dictionaryFile, trie, opts)
+}
+
+// readPlainWordlist loads a newline-delimited wordlist from r into the trie
+// under the rules of loadPlainWordlist.
+func readPlainWordlist(ctx context.Context, r io.Reader, trie *TrieNode, opts loadOptions) (int, error) {
+	debug := opts.debug
+	scanner := newLineScanner(r)
+	wordCount := 0
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("loading stopped after %d words: %w", wordCount, err)
+		}
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" {
+			continue
+		}
+
+		// Skip capitalized words (proper nouns)
+		if isCapitalized(word) && !opts.includeProper {
+			if opts.skips != nil {
+				opts.skips.ProperNouns++
+			}
+			if debug {
+				logger.Debug("skipping proper noun", "word", word)
+			}
+			continue
+		}
+
+		if strings.ContainsRune(word, '\'') {
+			if opts.skips != nil {
+				opts.skips.Possessives++
+			}
+			continue
+		}
+
+		if trie.Insert(strings.ToLower(word)) {
+			wordCount++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("scanning dictionary file: %w", err)
+	}
+
+	return wordCount, nil
+}
+
+// readWordList reads a newline-delimited list of words, lowercasing each one.
+// Blank lines and lines starting with # are ignored.
+func readWordList(listPath string) ([]string, error) {
+	listFile, err := openDictionary(listPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening word list: %w", err)
+	}
+	defer listFile.Close()
+
+	words, err := scanWordList(listFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading word list %s: %w", listPath, err)
+	}
+	return words, nil
+}
+
+// scanWordList reads a word list in the format readWordList accepts.
+func scanWordList(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := newLineScanner(r)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, strings.ToLower(word))
+	}
+	return words, scanner.Err()
+}
+
+// applyAllowlist inserts every word in the allowlist file into the trie and
+// returns how many of them were not already there.
+func applyAllowlist(trie *TrieNode, allowlistPath string) (int, error) {
+	words, err := readWordList(allowlistPath)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, word := range words {
+		if trie.Insert(word) {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// applyVariants reads a spelling variant file, one pair of words per line
+// such as "color colour", with blank lines and # comments ignored, and
+// inserts the second word of each pair whose first word is in the trie. It
+// returns how many variants were added. Only the listed forms are mapped,
+// so plurals and other inflections need lines of their own.
+func applyVariants(trie *TrieNode, variantsPath string) (int, error) {
+	lines, err := readWordList(variantsPath)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return added, fmt.Errorf("variant file %s: expected two words on %q", variantsPath, line)
+		}
+		if trie.Search(fields[0]) && trie.Insert(fields[1]) {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// applyBlocklist deletes every word in the blocklist file from the trie and
+// returns how many were actually present.
+func applyBlocklist(trie *TrieNode, blocklistPath string) (int, error) {
+	words, err := readWordList(blocklistPath)
+	if err != nil {
+		return 0, err
+	}
+	return deleteWords(trie, words), nil
+}
+
+// deleteWords deletes words from the trie and returns how many were present.
+func deleteWords(trie *TrieNode, words []string) int {
+	removed := 0
+	for _, word := range words {
+		if trie.Delete(word) {
+			removed++
+		}
+	}
+	return removed
+}
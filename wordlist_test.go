@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTempFile writes content to a new file named name in a per-test
+// temporary directory and returns its path.
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDetectDictionaryFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		expected string
+	}{
+		{"prolog extension", "wn_s.pl", "anything", formatWordNet},
+		{"sniffed wordnet", "dict.txt", "\ns(100000001,1,'cat',n,1,3).\n", formatWordNet},
+		{"sniffed plain", "words", "apple\nbanana\n", formatPlain},
+		{"empty file", "empty.txt", "", formatPlain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, tt.filename, tt.content)
+			format, err := detectDictionaryFormat(path)
+			if err != nil {
+				t.Fatalf("detectDictionaryFormat failed: %v", err)
+			}
+			if format != tt.expected {
+				t.Errorf("detectDictionaryFormat() = %q, expected %q", format, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadPlainWordlist(t *testing.T) {
+	path := writeTempFile(t, "words", "apple\n  Banana\nCherry\n\nrunner\n")
+
+	trie := NewTrieNode()
+	wordCount, err := loadPlainWordlist(context.Background(), path, trie, loadOptions{})
+	if err != nil {
+		t.Fatalf("loadPlainWordlist failed: %v", err)
+	}
+
+	if wordCount != 2 {
+		t.Errorf("Expected word count 2, got %d", wordCount)
+	}
+	for _, word := range []string{"apple", "runner"} {
+		if !trie.Search(word) {
+			t.Errorf("Expected '%s' to be in trie", word)
+		}
+	}
+	// No part-of-speech forms are generated for plain wordlists
+	if trie.Search("apples") {
+		t.Error("Expected 'apples' to not be generated from a plain wordlist")
+	}
+	if trie.Search("banana") || trie.Search("cherry") {
+		t.Error("Expected capitalized entries to be skipped")
+	}
+}
+
+func TestLoadDictionaryFile(t *testing.T) {
+	t.Run("plain wordlist", func(t *testing.T) {
+		path := writeTempFile(t, "words.txt", "quartile\ntile\n")
+		trie := NewTrieNode()
+		if _, err := loadDictionaryFile(context.Background(), path, formatAuto, trie, loadOptions{}); err != nil {
+			t.Fatalf("loadDictionaryFile failed: %v", err)
+		}
+		if !trie.Search("quartile") || !trie.Search("tile") {
+			t.Error("Expected plain wordlist entries to be searchable")
+		}
+	})
+
+	t.Run("wordnet", func(t *testing.T) {
+		path := writeTempFile(t, "dict.txt", "s(100000001,1,'cat',n,1,3).\n")
+		trie := NewTrieNode()
+		if _, err := loadDictionaryFile(context.Background(), path, formatAuto, trie, loadOptions{}); err != nil {
+			t.Fatalf("loadDictionaryFile failed: %v", err)
+		}
+		if !trie.Search("cats") {
+			t.Error("Expected WordNet forms to be generated")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		trie := NewTrieNode()
+		if _, err := loadDictionaryFile(context.Background(), "/nonexistent/words.txt", formatAuto, trie, loadOptions{}); err == nil {
+			t.Error("Expected error for missing dictionary")
+		}
+	})
+}
+
+func TestLoadDictionaryFile_Scowl(t *testing.T) {
+	// SCOWL lists are already inflected; a .pl-looking name must not matter
+	path := writeTempFile(t, "scowl.pl", "run\nruns\nran\nrunning\ngoose\ngeese\nwolf\nwolves\ncat's\n")
+
+	trie := NewTrieNode()
+	wordCount, err := loadDictionaryFile(context.Background(), path, formatScowl, trie, loadOptions{})
+	if err != nil {
+		t.Fatalf("loadDictionaryFile failed: %v", err)
+	}
+
+	for _, word := range []string{"ran", "running", "geese", "wolves"} {
+		if !trie.Search(word) {
+			t.Errorf("Expected inflected form '%s' to be in trie", word)
+		}
+	}
+	// The WordNet generators must not run over SCOWL entries
+	for _, word := range []string{"runed", "runing", "gooses", "wolfs", "geeses"} {
+		if trie.Search(word) {
+			t.Errorf("Expected generated form '%s' to not be in trie", word)
+		}
+	}
+	if trie.Search("cat's") {
+		t.Error("Expected possessive to be skipped")
+	}
+	if wordCount != 8 {
+		t.Errorf("Expected word count 8, got %d", wordCount)
+	}
+}
+
+func TestResolveDictionaryFormat_Unknown(t *testing.T) {
+	_, err := resolveDictionaryFormat("words.txt", "xml")
+	if err == nil {
+		t.Fatal("Expected error for unknown dictionary format")
+	}
+}
+
+func TestApplyBlocklist(t *testing.T) {
+	trie := NewTrieNode()
+	for _, word := range []string{"cat", "cats", "catalog", "dog"} {
+		trie.Insert(word)
+	}
+	path := writeTempFile(t, "blocklist.txt", "# archaic words\nCATS\n\ndog\nmissing\n")
+
+	removed, err := applyBlocklist(trie, path)
+	if err != nil {
+		t.Fatalf("applyBlocklist failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 words removed, got %d", removed)
+	}
+	if trie.Search("cats") || trie.Search("dog") {
+		t.Error("Expected blocklisted words to be removed")
+	}
+	if !trie.Search("cat") || !trie.Search("catalog") {
+		t.Error("Expected other words to remain")
+	}
+
+	if _, err := applyBlocklist(trie, "/nonexistent/blocklist.txt"); err == nil {
+		t.Error("Expected error for missing blocklist")
+	}
+}
+
+func TestApplyAllowlist(t *testing.T) {
+	trie := NewTrieNode()
+	path := writeTempFile(t, "allowlist.txt", "Quartile\n# slang\nyeet\n")
+
+	added, err := applyAllowlist(trie, path)
+	if err != nil {
+		t.Fatalf("applyAllowlist failed: %v", err)
+	}
+	if added != 2 {
+		t.Errorf("Expected 2 words added, got %d", added)
+	}
+	if !trie.Search("quartile") || !trie.Search("yeet") {
+		t.Error("Expected allowlisted words to be searchable")
+	}
+}
+
+func TestApplyVariants(t *testing.T) {
+	trie := NewTrieNode()
+	trie.Insert("color")
+	trie.Insert("centre")
+	path := writeTempFile(t, "variants.txt", "# American British\nColor Colour\ncenter centre\nfavor favour\n")
+
+	added, err := applyVariants(trie, path)
+	if err != nil {
+		t.Fatalf("applyVariants failed: %v", err)
+	}
+	if added != 1 || !trie.Search("colour") {
+		t.Errorf("Expected colour to be added alone, got %d added", added)
+	}
+	if trie.Search("favour") {
+		t.Error("Expected no variant for a word missing from the dictionary")
+	}
+
+	bad := writeTempFile(t, "bad.txt", "color\n")
+	if _, err := applyVariants(trie, bad); err == nil || !strings.Contains(err.Error(), "expected two words") {
+		t.Errorf("Expected an error for a line without a pair, got %v", err)
+	}
+}
+
+func TestRunWithOptions_Variants(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'color',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "co\nlo\nur\n")
+	variantsPath := writeTempFile(t, "variants.txt", "color colour\n")
+
+	run := func(variants string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, variantsPath: variants, format: outputQuiet}
+		if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+			t.Fatalf("runWithOptions() unexpected error: %v", err)
+		}
+		return buf.String()
+	}
+
+	if out := run(""); strings.Contains(out, "colour") {
+		t.Errorf("Expected no British spelling without a variant file, got %q", out)
+	}
+	if out := run(variantsPath); !strings.Contains(out, "colour\n") {
+		t.Errorf("Expected colour once the variant file is loaded, got %q", out)
+	}
+}
+
+func TestRunWithOptions_Allowlist(t *testing.T) {
+	dictPath := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).")
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ye\net\n")
+	allowlistPath := writeTempFile(t, "allowlist.txt", "yeet\n")
+
+	var buf bytes.Buffer
+	opts := options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}, allowlistPath: allowlistPath, debug: true, diagnostics: &buf}
+	if err := runWithOptions(context.Background(), opts, &buf); err != nil {
+		t.Fatalf("runWithOptions() unexpected error: %v", err)
+	}
+
+	// cat and cats from WordNet plus one allowlisted word
+	if !strings.Contains(buf.String(), "Loaded 3 words") {
+		t.Errorf("Expected allowlisted word in the word total, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "yeet") {
+		t.Errorf("Expected allowlisted word to be found, got:\n%s", buf.String())
+	}
+}
+
+// writeGzipFile writes content gzip-compressed to a temporary file.
+func writeGzipFile(t *testing.T, name, content string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return writeTempFile(t, name, buf.String())
+}
+
+func TestLoadDictionaryFile_Gzip(t *testing.T) {
+	t.Run("wordnet by extension", func(t *testing.T) {
+		path := writeGzipFile(t, "wn_s.pl.gz", "s(100000001,1,'cat',n,1,3).\ns(100000002,1,'dog',n,1,3).")
+		format, err := detectDictionaryFormat(path)
+		if err != nil || format != formatWordNet {
+			t.Fatalf("detectDictionaryFormat() = %q, %v, expected wordnet", format, err)
+		}
+
+		trie := NewTrieNode()
+		if _, err := loadDictionaryFile(context.Background(), path, formatAuto, trie, loadOptions{}); err != nil {
+			t.Fatalf("loadDictionaryFile() error = %v", err)
+		}
+		for _, word := range []string{"cat", "cats", "dog", "dogs"} {
+			if !trie.Search(word) {
+				t.Errorf("Expected %q from the gzipped dictionary", word)
+			}
+		}
+	})
+
+	t.Run("wordlist by magic bytes", func(t *testing.T) {
+		// No .gz extension: the gzip header alone triggers decompression
+		path := writeGzipFile(t, "words", "apple\nbanana\n")
+
+		trie := NewTrieNode()
+		count, err := loadDictionaryFile(context.Background(), path, formatAuto, trie, loadOptions{})
+		if err != nil {
+			t.Fatalf("loadDictionaryFile() error = %v", err)
+		}
+		if count != 2 || !trie.Search("apple") || !trie.Search("banana") {
+			t.Errorf("Expected apple and banana from the gzipped wordlist, got %d words", count)
+		}
+	})
+
+	t.Run("corrupt gzip", func(t *testing.T) {
+		path := writeTempFile(t, "words.gz", "\x1f\x8bnot really gzip")
+		if _, err := loadDictionaryFile(context.Background(), path, formatPlain, NewTrieNode(), loadOptions{}); err == nil {
+			t.Error("Expected an error for a corrupt gzip file")
+		}
+	})
+}
+
+func TestReaders_LongLines(t *testing.T) {
+	// Well past bufio.Scanner's 64KB default token limit
+	longLine := strings.Repeat("x", 200*1024)
+
+	t.Run("wordnet", func(t *testing.T) {
+		path := writeTempFile(t, "dict.pl", "s(100000001,1,'cat',n,1,3).\n"+longLine+"\ns(100000002,1,'dog',n,1,3).\n")
+		trie := NewTrieNode()
+		if _, err := loadDictionary(context.Background(), path, trie, false); err != nil {
+			t.Fatalf("loadDictionary() error = %v", err)
+		}
+		if !trie.Search("dog") {
+			t.Error("Expected the entry after the long line to load")
+		}
+	})
+
+	t.Run("wordlist", func(t *testing.T) {
+		path := writeTempFile(t, "words.txt", "apple\n"+longLine+"\nbanana\n")
+		trie := NewTrieNode()
+		if _, err := loadPlainWordlist(context.Background(), path, trie, loadOptions{}); err != nil {
+			t.Fatalf("loadPlainWordlist() error = %v", err)
+		}
+		if !trie.Search("banana") {
+			t.Error("Expected the word after the long line to load")
+		}
+	})
+
+	t.Run("puzzle", func(t *testing.T) {
+		path := writeTempFile(t, "puzzle.txt", "ab\n"+longLine+"\ncd\n")
+		tiles, err := readPuzzle(path, false, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("readPuzzle() error = %v", err)
+		}
+		if len(tiles) != 3 || tiles[2] != "cd" {
+			t.Errorf("Expected 3 tiles ending in cd, got %d", len(tiles))
+		}
+	})
+}
+
+func TestDictionaryOpenError_Permission(t *testing.T) {
+	denied := &fs.PathError{Op: "open", Path: "words.txt", Err: fs.ErrPermission}
+	err := dictionaryOpenError("words.txt", denied)
+	if !errors.Is(err, ErrDictionaryDenied) {
+		t.Fatalf("Expected ErrDictionaryDenied, got %v", err)
+	}
+	if errors.Is(err, ErrDictionaryNotFound) {
+		t.Errorf("Expected a permission error not to read as not found, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "words.txt") || !strings.Contains(err.Error(), "chmod") {
+		t.Errorf("Expected the path and a chmod hint, got %q", err)
+	}
+
+	missing := &fs.PathError{Op: "open", Path: "words.txt", Err: fs.ErrNotExist}
+	if err := dictionaryOpenError("words.txt", missing); err != missing {
+		t.Errorf("Expected other errors unchanged, got %v", err)
+	}
+}
+
+func TestRunWithOptions_UnreadableDictionary(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read files without read permission")
+	}
+	dictPath := writeTempFile(t, "words.txt", "castle\n")
+	if err := os.Chmod(dictPath, 0); err != nil {
+		t.Skipf("cannot remove read permission: %v", err)
+	}
+	puzzlePath := writeTempFile(t, "puzzle.txt", "ca\nst\nle\n")
+
+	err := runWithOptions(context.Background(), options{dictionaryPath: dictPath, puzzlePaths: []string{puzzlePath}}, io.Discard)
+	if !errors.Is(err, ErrDictionaryDenied) {
+		t.Errorf("Expected ErrDictionaryDenied, got %v", err)
+	}
+}
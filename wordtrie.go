@@ -0,0 +1,84 @@
+package main
+
+// wordTrie is the read-only view of a dictionary that solving needs. A
+// TrieNode built in memory implements it, and so does a TrieFile, which
+// answers straight from the mapped file without building any nodes.
+type wordTrie interface {
+	// Search returns true if the word exists in the trie.
+	Search(word string) bool
+	// HasPrefix returns true if any word in the trie begins with prefix.
+	HasPrefix(prefix string) bool
+	// CountPrefix returns the number of words that begin with prefix.
+	CountPrefix(prefix string) int
+	// Walk calls fn for every word in lexicographic order.
+	Walk(fn func(word string))
+	// at returns the node reached by following prefix, or nil if no word
+	// begins with it.
+	at(prefix string) trieCursor
+}
+
+// trieCursor is one node of a wordTrie, for searches that step through the
+// trie a letter at a time instead of looking up whole words.
+type trieCursor interface {
+	// isWord reports whether a word ends at this node.
+	isWord() bool
+	// next returns the child reached by char, or nil if there is none.
+	next(char rune) trieCursor
+	// children calls fn for every child of this node.
+	children(fn func(char rune, child trieCursor))
+}
+
+func (t *TrieNode) at(prefix string) trieCursor {
+	if node := t.find(prefix); node != nil {
+		return node
+	}
+	return nil
+}
+
+func (t *TrieNode) isWord() bool {
+	return t.IsEnd
+}
+
+func (t *TrieNode) next(char rune) trieCursor {
+	if child := t.child(char); child != nil {
+		return child
+	}
+	return nil
+}
+
+func (t *TrieNode) children(fn func(char rune, child trieCursor)) {
+	t.eachChild(func(char rune, child *TrieNode) {
+		fn(char, child)
+	})
+}
+
+// fileNode is a node of a TrieFile, named by its offset in the file.
+type fileNode struct {
+	file   *TrieFile
+	offset uint32
+}
+
+func (t *TrieFile) at(prefix string) trieCursor {
+	if offset, ok := t.find(prefix); ok {
+		return fileNode{t, offset}
+	}
+	return nil
+}
+
+func (n fileNode) isWord() bool {
+	return n.file.data[n.offset]&trieFileEnd != 0
+}
+
+func (n fileNode) next(char rune) trieCursor {
+	if child, ok := n.file.child(n.offset, char); ok {
+		return fileNode{n.file, child}
+	}
+	return nil
+}
+
+func (n fileNode) children(fn func(char rune, child trieCursor)) {
+	for i := uint32(0); i < n.file.childCount(n.offset); i++ {
+		char, child := n.file.childAt(n.offset, i)
+		fn(char, fileNode{n.file, child})
+	}
+}